@@ -0,0 +1,96 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+// AppendableInputStream is an InputStream that accepts more text after it is
+// created, for input that arrives in chunks such as lines typed into a REPL
+// or packets read from a socket.
+//
+// Until Close is called the end of the stream is provisional. A lexer reading
+// from it never emits EOF there: if matching the current token needs to look
+// at the character after the available text, NextToken rewinds to the start
+// of the token and returns a token of type TokenNeedMoreInput. Append more
+// text and call NextToken again to resume. Once the stream is closed, lexing
+// finishes exactly as it would over an InputStream.
+//
+// Tokens already returned are never re-lexed, so a caller only pays for the
+// text it has not yet tokenized. The stream keeps all text appended to it.
+// The token that needed more input is matched again from its start, though,
+// including the fragments of a rule that called More(): the lexer's mode,
+// type, channel and text are restored, but the custom actions of those
+// fragments run again.
+//
+// A CaseChangingStream over an AppendableInputStream passes the provisional
+// end through, so case-insensitive grammars can lex growing input too.
+// Appending and closing are done on the AppendableInputStream.
+type AppendableInputStream struct {
+	*InputStream
+
+	closed bool
+
+	// hitEnd records that LA looked past the available text since the lexer
+	// last started matching.
+	hitEnd bool
+}
+
+// NewAppendableInputStream creates an open stream holding data.
+func NewAppendableInputStream(data string) *AppendableInputStream {
+	return &AppendableInputStream{InputStream: NewInputStream(data)}
+}
+
+// Append adds text to the end of the stream. It panics if the stream has been
+// closed.
+func (as *AppendableInputStream) Append(text string) {
+	if as.closed {
+		panic("cannot append to a closed stream")
+	}
+
+	as.data = append(as.data, []rune(text)...)
+	as.size = len(as.data)
+	as.lineIndex = nil
+}
+
+// Close marks the end of the available text as the real end of input.
+func (as *AppendableInputStream) Close() {
+	as.closed = true
+}
+
+// IsClosed reports whether Close has been called.
+func (as *AppendableInputStream) IsClosed() bool {
+	return as.closed
+}
+
+func (as *AppendableInputStream) LA(offset int) int {
+	c := as.InputStream.LA(offset)
+	if c == TokenEOF && offset > 0 && !as.closed {
+		as.hitEnd = true
+	}
+
+	return c
+}
+
+var (
+	_ incompleteCharStream = &AppendableInputStream{}
+	_ incompleteCharStream = &CaseChangingStream{}
+)
+
+func (as *AppendableInputStream) resetHitEnd() {
+	as.hitEnd = false
+}
+
+func (as *AppendableInputStream) needsMoreInput() bool {
+	return as.hitEnd
+}
+
+// incompleteCharStream is implemented by char streams whose end of input is
+// provisional. The lexer ATN simulator resets the stream before matching a
+// token and asks afterwards whether the match ran into the provisional end.
+// Streams wrapping another CharStream implement it by forwarding to the
+// wrapped stream.
+type incompleteCharStream interface {
+	IsClosed() bool
+	resetHitEnd()
+	needsMoreInput() bool
+}
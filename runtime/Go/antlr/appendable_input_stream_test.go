@@ -0,0 +1,93 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+func TestAppendableInputStreamNeedMoreInput(t *testing.T) {
+	assert := assertNew(t)
+	input := NewAppendableInputStream("x = ab")
+	lexer := NewLexerB(input)
+
+	// "ab" could still grow into a longer ID
+	tokens := lexer.GetAllTokens()
+	assert.Equal(4, len(tokens))
+	assert.Equal(LexerBASSIGN, tokens[2].GetTokenType())
+	t1 := lexer.NextToken()
+	assert.Equal(TokenNeedMoreInput, t1.GetTokenType())
+	assert.Equal(4, t1.GetStart())
+	assert.Equal(4, input.Index())
+
+	input.Append("c+1")
+	t2 := lexer.NextToken()
+	assert.Equal(LexerBID, t2.GetTokenType())
+	assert.Equal("abc", t2.GetText())
+	assert.Equal(4, t2.GetColumn())
+	assert.Equal(7, t2.GetEndColumn())
+	assert.Equal(LexerBPLUS, lexer.NextToken().GetTokenType())
+	assert.Equal(TokenNeedMoreInput, lexer.NextToken().GetTokenType())
+
+	input.Close()
+	t3 := lexer.NextToken()
+	assert.Equal(LexerBINT, t3.GetTokenType())
+	assert.Equal("1", t3.GetText())
+	assert.Equal(TokenEOF, lexer.NextToken().GetTokenType())
+	assert.Equal(TokenEOF, lexer.NextToken().GetTokenType())
+	assert.Panics(func() { input.Append("2") })
+}
+
+func TestAppendableInputStreamMatchesInputStream(t *testing.T) {
+	assert := assertNew(t)
+	chunks := []string{"a", "bc = 1", "2", "3 + x", "yz;", " q * 4"}
+
+	input := NewAppendableInputStream("")
+	lexer := NewLexerB(input)
+	var actual []Token
+	src := ""
+	for _, chunk := range chunks {
+		input.Append(chunk)
+		src += chunk
+		actual = append(actual, lexer.GetAllTokens()...)
+	}
+	input.Close()
+	actual = append(actual, lexer.GetAllTokens()...)
+
+	expected := NewLexerB(NewInputStream(src)).GetAllTokens()
+	assert.Equal(len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(expected[i].GetTokenType(), actual[i].GetTokenType())
+		assert.Equal(expected[i].GetText(), actual[i].GetText())
+		assert.Equal(expected[i].GetColumn(), actual[i].GetColumn())
+	}
+}
+
+func TestAppendableInputStreamCaseChanging(t *testing.T) {
+	assert := assertNew(t)
+	input := NewAppendableInputStream("AB")
+	lexer := NewLexerB(NewCaseChangingStream(input, false))
+
+	assert.Equal(TokenNeedMoreInput, lexer.NextToken().GetTokenType())
+	assert.Equal(TokenNeedMoreInput, lexer.NextToken().GetTokenType())
+
+	input.Append("c;")
+	t1 := lexer.NextToken()
+	assert.Equal(LexerBID, t1.GetTokenType())
+	assert.Equal("ABc", t1.GetText())
+	assert.Equal(TokenNeedMoreInput, lexer.NextToken().GetTokenType())
+
+	input.Append("D")
+	assert.Equal(LexerBSEMI, lexer.NextToken().GetTokenType())
+	assert.Equal(TokenNeedMoreInput, lexer.NextToken().GetTokenType())
+	input.Close()
+	assert.Equal("D", lexer.NextToken().GetText())
+	assert.Equal(TokenEOF, lexer.NextToken().GetTokenType())
+
+	// over a complete stream the wrapper is closed
+	lexer = NewLexerB(NewCaseChangingStream(NewInputStream("AB"), false))
+	assert.Equal("AB", lexer.NextToken().GetText())
+	assert.Equal(TokenEOF, lexer.NextToken().GetTokenType())
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+// ArenaTokenFactoryDefaultChunkSize is the number of tokens allocated at once
+// by an ArenaTokenFactory created with a chunk size of zero.
+const ArenaTokenFactoryDefaultChunkSize = 1024
+
+// arenaToken keeps a CommonToken next to the BaseToken it points to, so both
+// come from a single allocation.
+type arenaToken struct {
+	token CommonToken
+	base  BaseToken
+}
+
+// ArenaTokenFactory is a TokenFactory that carves tokens out of chunks of
+// preallocated storage instead of allocating each one on the heap. Lexing a
+// large input then costs one allocation per chunk rather than two per token,
+// which greatly reduces garbage collection work.
+//
+// The tokens are ordinary CommonTokens. Their text is not copied: GetText
+// reads it from the input stream when called, so the factory must not be used
+// with streams that discard input, such as UnbufferedCharStream.
+//
+// A chunk stays in memory as long as any of its tokens is referenced. An
+// ArenaTokenFactory is not safe for concurrent use; give each lexer its own:
+//
+//	lexer.SetTokenFactory(antlr.NewArenaTokenFactory(0))
+type ArenaTokenFactory struct {
+	chunkSize int
+
+	// free is the unused tail of the current chunk.
+	free []arenaToken
+}
+
+// NewArenaTokenFactory creates a factory allocating chunkSize tokens at a
+// time, or ArenaTokenFactoryDefaultChunkSize if chunkSize is not positive.
+func NewArenaTokenFactory(chunkSize int) *ArenaTokenFactory {
+	if chunkSize <= 0 {
+		chunkSize = ArenaTokenFactoryDefaultChunkSize
+	}
+
+	return &ArenaTokenFactory{chunkSize: chunkSize}
+}
+
+func (a *ArenaTokenFactory) Create(source *TokenSourceCharStreamPair, ttype int, text string, channel, start, stop, line, column, endLine, endColumn int) Token {
+	if len(a.free) == 0 {
+		a.free = make([]arenaToken, a.chunkSize)
+	}
+	at := &a.free[0]
+	a.free = a.free[1:]
+
+	at.base = BaseToken{
+		source:     source,
+		tokenType:  ttype,
+		channel:    channel,
+		start:      start,
+		stop:       stop,
+		tokenIndex: -1,
+		line:       line,
+		column:     column,
+		endLine:    endLine,
+		endColumn:  endColumn,
+		text:       text,
+	}
+	at.token.BaseToken = &at.base
+
+	return &at.token
+}
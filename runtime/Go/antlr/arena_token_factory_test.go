@@ -0,0 +1,57 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArenaTokenFactory(t *testing.T) {
+	assert := assertNew(t)
+	src := strings.Repeat("abc = 123; ", 10)
+
+	lexer := NewLexerB(NewInputStream(src))
+	lexer.SetTokenFactory(NewArenaTokenFactory(4))
+	actual := lexer.GetAllTokens()
+	expected := NewLexerB(NewInputStream(src)).GetAllTokens()
+
+	assert.Equal(len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(expected[i].(*CommonToken).String(), actual[i].(*CommonToken).String())
+		assert.Equal(expected[i].GetEndColumn(), actual[i].GetEndColumn())
+	}
+
+	// tokens sharing a chunk stay independent
+	actual[0].SetText("x")
+	actual[0].SetTokenIndex(7)
+	assert.Equal("x", actual[0].GetText())
+	assert.Equal(" ", actual[1].GetText())
+	assert.Equal(-1, actual[1].GetTokenIndex())
+}
+
+func benchmarkLexTokenFactory(b *testing.B, newFactory func() TokenFactory) {
+	input := NewInputStream(strings.Repeat("abc = 123; x = y + 4 * z; ", 1000))
+	lexer := NewLexerB(input)
+	lexer.GetAllTokens() // warm up the DFA
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		input.Seek(0)
+		lexer.reset()
+		lexer.SetTokenFactory(newFactory())
+		for lexer.NextToken().GetTokenType() != TokenEOF {
+		}
+	}
+}
+
+func BenchmarkLexCommonTokenFactory(b *testing.B) {
+	benchmarkLexTokenFactory(b, func() TokenFactory { return CommonTokenFactoryDEFAULT })
+}
+
+func BenchmarkLexArenaTokenFactory(b *testing.B) {
+	benchmarkLexTokenFactory(b, func() TokenFactory { return NewArenaTokenFactory(0) })
+}
@@ -37,6 +37,9 @@ type ATN struct {
 	ruleToTokenType []int
 
 	states []ATNState
+
+	// nameMaps caches the name maps of the recognizers using the ATN.
+	nameMaps nameMaps
 }
 
 func NewATN(grammarType int, maxTokenType int) *ATN {
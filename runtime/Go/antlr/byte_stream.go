@@ -0,0 +1,169 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+// ByteStream is a CharStream for binary input where every byte is one symbol
+// with a value of 0-255. No UTF-8 decoding takes place, so grammars match
+// bytes with ranges such as '\u0000'..'\u00FF'.
+//
+// GetText returns the raw bytes of an interval as a Go string, which is not
+// necessarily valid UTF-8. The GetBytes methods return them as a []byte.
+type ByteStream struct {
+	index int
+	data  []byte
+	size  int
+
+	// lineIndex is built on first use by the position lookup methods.
+	lineIndex *LineIndex
+}
+
+// NewByteStream creates a stream over data. The slice is not copied and must
+// not be modified while the stream is in use.
+func NewByteStream(data []byte) *ByteStream {
+
+	bs := new(ByteStream)
+
+	bs.index = 0
+	bs.data = data
+	bs.size = len(bs.data)
+
+	return bs
+}
+
+func (bs *ByteStream) reset() {
+	bs.index = 0
+}
+
+func (bs *ByteStream) Consume() {
+	if bs.index >= bs.size {
+		// assert bs.LA(1) == TokenEOF
+		panic("cannot consume EOF")
+	}
+	bs.index++
+}
+
+func (bs *ByteStream) LA(offset int) int {
+
+	if offset == 0 {
+		return 0 // nil
+	}
+	if offset < 0 {
+		offset++ // e.g., translate LA(-1) to use offset=0
+	}
+	pos := bs.index + offset - 1
+
+	if pos < 0 || pos >= bs.size { // invalid
+		return TokenEOF
+	}
+
+	return int(bs.data[pos])
+}
+
+func (bs *ByteStream) LT(offset int) int {
+	return bs.LA(offset)
+}
+
+func (bs *ByteStream) Index() int {
+	return bs.index
+}
+
+func (bs *ByteStream) Size() int {
+	return bs.size
+}
+
+// mark/release do nothing we have entire buffer
+func (bs *ByteStream) Mark() int {
+	return -1
+}
+
+func (bs *ByteStream) Release(marker int) {
+}
+
+func (bs *ByteStream) Seek(index int) {
+	if index <= bs.index {
+		bs.index = index // just jump don't update stream state (line,...)
+		return
+	}
+	// seek forward
+	bs.index = intMin(index, bs.size)
+}
+
+// GetBytes returns the bytes from start to stop inclusive. The returned slice
+// shares memory with the stream's data.
+func (bs *ByteStream) GetBytes(start int, stop int) []byte {
+	if stop >= bs.size {
+		stop = bs.size - 1
+	}
+	if start >= bs.size || stop < start {
+		return nil
+	}
+
+	return bs.data[start : stop+1]
+}
+
+func (bs *ByteStream) GetBytesFromTokens(start, stop Token) []byte {
+	if start != nil && stop != nil {
+		return bs.GetBytes(start.GetStart(), stop.GetStop())
+	}
+
+	return nil
+}
+
+func (bs *ByteStream) GetBytesFromInterval(i *Interval) []byte {
+	return bs.GetBytes(i.Start, i.Stop)
+}
+
+func (bs *ByteStream) GetText(start int, stop int) string {
+	return string(bs.GetBytes(start, stop))
+}
+
+func (bs *ByteStream) GetTextFromTokens(start, stop Token) string {
+	return string(bs.GetBytesFromTokens(start, stop))
+}
+
+func (bs *ByteStream) GetTextFromInterval(i *Interval) string {
+	return bs.GetText(i.Start, i.Stop)
+}
+
+func (*ByteStream) GetSourceName() string {
+	return "Obtained from bytes"
+}
+
+func (bs *ByteStream) String() string {
+	return string(bs.data)
+}
+
+// GetLineIndex returns the line table of the stream, building it on first
+// use. Lines end at '\n' bytes and every byte is one column.
+func (bs *ByteStream) GetLineIndex() *LineIndex {
+	if bs.lineIndex == nil {
+		data := make([]rune, len(bs.data))
+		for i, b := range bs.data {
+			data[i] = rune(b)
+		}
+		bs.lineIndex = newLineIndex(data, nil)
+	}
+
+	return bs.lineIndex
+}
+
+// PositionOf returns the line (1..n) and column (0..n-1) of the byte at
+// index, for example the start of a token or of a RecognitionException's
+// offending token.
+func (bs *ByteStream) PositionOf(index int) (line, column int) {
+	return bs.GetLineIndex().PositionOf(index)
+}
+
+// OffsetOf returns the index of the byte at line and column, or -1 if the
+// line does not exist.
+func (bs *ByteStream) OffsetOf(line, column int) int {
+	return bs.GetLineIndex().OffsetOf(line, column)
+}
+
+// LineText returns the raw bytes of line n without its line terminator.
+func (bs *ByteStream) LineText(n int) string {
+	start, stop := bs.GetLineIndex().lineSpan(n)
+	return string(bs.data[start:stop])
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+func TestByteStreamLA(t *testing.T) {
+	assert := assertNew(t)
+	input := NewByteStream([]byte{'a', 0x80, 0xE9, 0xFF})
+
+	assert.Equal(4, input.Size())
+	assert.Equal(int('a'), input.LA(1))
+	assert.Equal(0x80, input.LA(2))
+	assert.Equal(0xE9, input.LA(3))
+	assert.Equal(0xFF, input.LA(4))
+	assert.Equal(TokenEOF, input.LA(5))
+	assert.Equal(TokenEOF, input.LA(-1))
+
+	input.Consume()
+	input.Consume()
+	assert.Equal(2, input.Index())
+	assert.Equal(0x80, input.LA(-1))
+	assert.Equal(0xE9, input.LA(1))
+
+	input.Seek(1)
+	assert.Equal(0x80, input.LA(1))
+	input.Seek(10)
+	assert.Equal(4, input.Index())
+	assert.Equal(TokenEOF, input.LA(1))
+	assert.Panics(input.Consume)
+}
+
+func TestByteStreamGetBytes(t *testing.T) {
+	assert := assertNew(t)
+	data := []byte("ab\xe9\xffcd")
+	input := NewByteStream(data)
+
+	assert.Equal([]byte("b\xe9\xff"), input.GetBytes(1, 3))
+	assert.Equal("b\xe9\xff", input.GetText(1, 3))
+	assert.Equal([]byte("cd"), input.GetBytesFromInterval(NewInterval(4, 100)))
+	assert.Equal("cd", input.GetTextFromInterval(NewInterval(4, 100)))
+	assert.Nil(input.GetBytes(3, 2))
+	assert.Nil(input.GetBytes(6, 8))
+	assert.Equal(string(data), input.String())
+
+	start := newTestCommonToken(LexerBID, "", TokenDefaultChannel)
+	start.start, start.stop = 0, 1
+	stop := newTestCommonToken(LexerBID, "", TokenDefaultChannel)
+	stop.start, stop.stop = 2, 3
+	assert.Equal([]byte("ab\xe9\xff"), input.GetBytesFromTokens(start, stop))
+	assert.Equal("ab\xe9\xff", input.GetTextFromTokens(start, stop))
+	assert.Nil(input.GetBytesFromTokens(nil, stop))
+
+	// the EOF token covers the empty range after the last byte
+	eof := newTestCommonToken(TokenEOF, "", TokenDefaultChannel)
+	eof.start, eof.stop = 6, 5
+	assert.Nil(input.GetBytesFromTokens(eof, eof))
+	assert.Equal("", input.GetTextFromTokens(eof, eof))
+}
+
+func TestByteStreamLexer(t *testing.T) {
+	assert := assertNew(t)
+	lexer := NewLexerB(NewByteStream([]byte("ab\xe9cd=1;")))
+	lexer.RemoveErrorListeners()
+
+	// the default error handling drops the byte no rule Matches
+	var texts []string
+	var starts []int
+	for _, token := range lexer.GetAllTokens() {
+		texts = append(texts, token.GetText())
+		starts = append(starts, token.GetStart())
+	}
+	assert.Equal([]string{"ab", "cd", "=", "1", ";"}, texts)
+	assert.Equal([]int{0, 3, 5, 6, 7}, starts)
+}
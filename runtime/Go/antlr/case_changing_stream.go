@@ -0,0 +1,158 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"unicode"
+)
+
+// CaseChangingStream wraps an existing CharStream and presents every
+// character to the lexer in upper case or in lower case, so a grammar for a
+// case-insensitive language (SQL, Pascal, BASIC, ...) can spell its keywords
+// in one case only:
+//
+//	K_UPDATE : 'UPDATE';
+//
+// Only LA is affected. GetText and the other text methods are those of the
+// wrapped stream, so tokens keep the casing of the original input.
+//
+// Characters are folded with Unicode simple case folding: every character
+// of a case folding class, such as 'k', 'K' and the Kelvin sign U+212A, or
+// 'σ', 'ς' and 'Σ', is mapped to the same upper or lower case form. Folds
+// that would change the number of characters, such as 'ß' to "SS", are not
+// applied, as they would break the mapping between token indexes and the
+// input.
+type CaseChangingStream struct {
+	CharStream
+
+	upper bool
+}
+
+// NewCaseChangingStream returns a new CaseChangingStream that forces
+// all tokens read from the underlying stream to be either upper case
+// or lower case based on the upper argument.
+func NewCaseChangingStream(in CharStream, upper bool) *CaseChangingStream {
+	return &CaseChangingStream{
+		in, upper,
+	}
+}
+
+// LA gets the value of the symbol at offset from the current position
+// from the underlying CharStream and converts it to either upper case
+// or lower case.
+func (is *CaseChangingStream) LA(offset int) int {
+	in := is.CharStream.LA(offset)
+	if in < 0 {
+		// Such as antlr.TokenEOF which is -1
+		return in
+	}
+	if is.upper {
+		return int(foldUpper(rune(in)))
+	}
+	return int(foldLower(rune(in)))
+}
+
+// IsClosed reports whether the end of the wrapped stream is the end of
+// input. It is false only for an open AppendableInputStream, over which the
+// lexer returns TokenNeedMoreInput as it would without the wrapper.
+func (is *CaseChangingStream) IsClosed() bool {
+	if in, ok := is.CharStream.(incompleteCharStream); ok {
+		return in.IsClosed()
+	}
+	return true
+}
+
+func (is *CaseChangingStream) resetHitEnd() {
+	if in, ok := is.CharStream.(incompleteCharStream); ok {
+		in.resetHitEnd()
+	}
+}
+
+func (is *CaseChangingStream) needsMoreInput() bool {
+	if in, ok := is.CharStream.(incompleteCharStream); ok {
+		return in.needsMoreInput()
+	}
+	return false
+}
+
+// foldUpper returns the upper case form shared by all characters that fold
+// together with r.
+func foldUpper(r rune) rune {
+	if r < 0x80 {
+		if 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		return r
+	}
+
+	return foldClassUpper(r)
+}
+
+// foldLower returns the lower case form shared by all characters that fold
+// together with r.
+func foldLower(r rune) rune {
+	if r < 0x80 {
+		if 'A' <= r && r <= 'Z' {
+			r += 'a' - 'A'
+		}
+		return r
+	}
+
+	// the lower case mapping may leave the class, as U+0130 LATIN CAPITAL
+	// LETTER I WITH DOT ABOVE maps to 'i', which does not fold together with
+	// it
+	if u := foldClassUpper(r); unicode.ToLower(u) != u && inFoldClass(u, unicode.ToLower(u)) {
+		return unicode.ToLower(u)
+	}
+
+	// no member of the class has a lower case mapping within the class
+	return foldClassMin(r)
+}
+
+// foldClassUpper returns the upper case form of the case folding class of r:
+// the smallest member that is its own upper case and has a lower case
+// mapping. The choice depends on the class only, not on its member r, and
+// does not rely on the general category of the characters, since classes
+// such as the Roman numerals (Nl), the circled letters (So) or the Greek
+// letters with iota subscript (Lt) contain no Lu or Ll characters at all.
+// If there is no such member, it is the smallest member.
+func foldClassUpper(r rune) rune {
+	upper := rune(-1)
+	f := r
+	for {
+		if unicode.ToUpper(f) == f && unicode.ToLower(f) != f && (upper < 0 || f < upper) {
+			upper = f
+		}
+		if f = unicode.SimpleFold(f); f == r {
+			break
+		}
+	}
+
+	if upper < 0 {
+		return foldClassMin(r)
+	}
+	return upper
+}
+
+// inFoldClass reports whether c is in the case folding class of r.
+func inFoldClass(r, c rune) bool {
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f == c {
+			return true
+		}
+	}
+	return false
+}
+
+// foldClassMin returns the smallest member of the case folding class of r.
+func foldClassMin(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestCaseChangingStreamFolding(t *testing.T) {
+	assert := assertNew(t)
+
+	upper := []struct{ in, expected rune }{
+		{'a', 'A'}, {'Z', 'Z'}, {'1', '1'},
+		{'é', 'É'}, {'ς', 'Σ'}, {'σ', 'Σ'},
+		{'ſ', 'S'}, {'\u212A', 'K'}, {'\u2126', 'Ω'},
+		{'ß', 'ẞ'}, {'ǆ', 'Ǆ'}, {'ǅ', 'Ǆ'},
+		{'ⅰ', 'Ⅰ'}, {'Ⅰ', 'Ⅰ'}, {'ⓐ', 'Ⓐ'}, {'Ⓐ', 'Ⓐ'},
+		{'ᾀ', 'ᾈ'}, {'ᾈ', 'ᾈ'},
+	}
+	for _, test := range upper {
+		assert.Equal(test.expected, foldUpper(test.in))
+	}
+
+	lower := []struct{ in, expected rune }{
+		{'A', 'a'}, {'z', 'z'}, {'1', '1'},
+		{'É', 'é'}, {'ς', 'σ'}, {'Σ', 'σ'},
+		{'ſ', 's'}, {'\u212A', 'k'}, {'\u2126', 'ω'},
+		{'ẞ', 'ß'}, {'Ǆ', 'ǆ'}, {'ǅ', 'ǆ'},
+		{'Ⅰ', 'ⅰ'}, {'ⅰ', 'ⅰ'}, {'Ⓐ', 'ⓐ'}, {'ⓐ', 'ⓐ'},
+		{'ᾈ', 'ᾀ'}, {'ᾀ', 'ᾀ'},
+	}
+	for _, test := range lower {
+		assert.Equal(test.expected, foldLower(test.in))
+	}
+}
+
+func TestCaseChangingStreamFoldingClasses(t *testing.T) {
+	// every member of a class folds to the same character, in the class,
+	// and no two classes fold alike
+	classes := [][]rune{
+		{'a', 'A'},
+		{'k', 'K', '\u212A'},     // Kelvin sign
+		{'s', 'S', 'ſ'},          // long s
+		{'σ', 'ς', 'Σ'},          // final sigma
+		{'i', 'I'}, {'İ'}, {'ı'}, // Turkish dotted and dotless i
+		{'ǆ', 'ǅ', 'Ǆ'},        // title case digraph
+		{'ⅰ', 'Ⅰ'}, {'ᾀ', 'ᾈ'}, // no Lu or Ll members
+	}
+	seenUpper := make(map[rune]bool)
+	seenLower := make(map[rune]bool)
+	for _, class := range classes {
+		upper, lower := foldUpper(class[0]), foldLower(class[0])
+		checkFoldClass(t, class[0], upper, lower)
+		for _, r := range class[1:] {
+			if foldUpper(r) != upper || foldLower(r) != lower {
+				t.Errorf("%U and %U fold differently: %U/%U and %U/%U", class[0], r, upper, lower, foldUpper(r), foldLower(r))
+			}
+		}
+		if seenUpper[upper] || seenLower[lower] {
+			t.Errorf("the class of %U folds like another class: %U/%U", class[0], upper, lower)
+		}
+		seenUpper[upper], seenLower[lower] = true, true
+	}
+}
+
+// TestCaseChangingStreamFoldingAllClasses checks the folding of every
+// character. It takes about half a second and is skipped with -short.
+func TestCaseChangingStreamFoldingAllClasses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("folding every character is slow")
+	}
+
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		upper, lower := foldUpper(r), foldLower(r)
+		checkFoldClass(t, r, upper, lower)
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if foldUpper(f) != upper || foldLower(f) != lower {
+				t.Fatalf("%U and %U fold differently: %U/%U and %U/%U", r, f, upper, lower, foldUpper(f), foldLower(f))
+			}
+		}
+	}
+}
+
+// checkFoldClass fails t unless upper and lower, the folded forms of r, are
+// in the case folding class of r.
+func checkFoldClass(t *testing.T, r, upper, lower rune) {
+	if upper != r && !inFoldClass(r, upper) || lower != r && !inFoldClass(r, lower) {
+		t.Fatalf("%U folds out of its class to %U/%U", r, upper, lower)
+	}
+}
+
+func TestCaseChangingStreamLexer(t *testing.T) {
+	assert := assertNew(t)
+	input := NewCaseChangingStream(NewInputStream("AbC = 42"), false)
+
+	assert.Equal(int('a'), input.LA(1))
+	assert.Equal(int('b'), input.LA(2))
+	assert.Equal(int('c'), input.LA(3))
+
+	tokens := NewLexerB(input).GetAllTokens()
+	assert.Equal(LexerBID, tokens[0].GetTokenType())
+	assert.Equal("AbC", tokens[0].GetText())
+	assert.Equal(LexerBINT, tokens[4].GetTokenType())
+}
@@ -4,9 +4,11 @@
 
 package antlr
 
-// TokenFactory creates CommonToken objects.
+// TokenFactory creates CommonToken objects. The line and column are those of
+// the token's first character; endLine and endColumn are the position just
+// past its last character.
 type TokenFactory interface {
-	Create(source *TokenSourceCharStreamPair, ttype int, text string, channel, start, stop, line, column int) Token
+	Create(source *TokenSourceCharStreamPair, ttype int, text string, channel, start, stop, line, column, endLine, endColumn int) Token
 }
 
 // CommonTokenFactory is the default TokenFactory implementation.
@@ -33,11 +35,13 @@ func NewCommonTokenFactory(copyText bool) *CommonTokenFactory {
 // explicitly copy token text when constructing tokens.
 var CommonTokenFactoryDEFAULT = NewCommonTokenFactory(false)
 
-func (c *CommonTokenFactory) Create(source *TokenSourceCharStreamPair, ttype int, text string, channel, start, stop, line, column int) Token {
+func (c *CommonTokenFactory) Create(source *TokenSourceCharStreamPair, ttype int, text string, channel, start, stop, line, column, endLine, endColumn int) Token {
 	t := NewCommonToken(source, ttype, channel, start, stop)
 
 	t.line = line
 	t.column = column
+	t.endLine = endLine
+	t.endColumn = endColumn
 
 	if text != "" {
 		t.SetText(text)
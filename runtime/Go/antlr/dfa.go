@@ -154,12 +154,12 @@ func (d *DFA) sortedStates() []*DFAState {
 	return vs
 }
 
-func (d *DFA) String(literalNames []string, symbolicNames []string) string {
+func (d *DFA) String(vocabulary Vocabulary) string {
 	if d.s0 == nil {
 		return ""
 	}
 
-	return NewDFASerializer(d, literalNames, symbolicNames).String()
+	return NewDFASerializer(d, vocabulary).String()
 }
 
 func (d *DFA) ToLexerString() string {
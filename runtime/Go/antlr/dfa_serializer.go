@@ -12,24 +12,20 @@ import (
 // DFASerializer is a DFA walker that knows how to dump them to serialized
 // strings.
 type DFASerializer struct {
-	dfa           *DFA
-	literalNames  []string
-	symbolicNames []string
+	dfa        *DFA
+	vocabulary Vocabulary
 }
 
-func NewDFASerializer(dfa *DFA, literalNames, symbolicNames []string) *DFASerializer {
-	if literalNames == nil {
-		literalNames = make([]string, 0)
-	}
-
-	if symbolicNames == nil {
-		symbolicNames = make([]string, 0)
+// NewDFASerializer creates a serializer labelling edges with the token names
+// of vocabulary, which may be nil to use VocabularyEMPTY.
+func NewDFASerializer(dfa *DFA, vocabulary Vocabulary) *DFASerializer {
+	if vocabulary == nil {
+		vocabulary = VocabularyEMPTY
 	}
 
 	return &DFASerializer{
-		dfa:           dfa,
-		literalNames:  literalNames,
-		symbolicNames: symbolicNames,
+		dfa:        dfa,
+		vocabulary: vocabulary,
 	}
 }
 
@@ -70,13 +66,9 @@ func (d *DFASerializer) String() string {
 func (d *DFASerializer) getEdgeLabel(i int) string {
 	if i == 0 {
 		return "EOF"
-	} else if d.literalNames != nil && i-1 < len(d.literalNames) {
-		return d.literalNames[i-1]
-	} else if d.symbolicNames != nil && i-1 < len(d.symbolicNames) {
-		return d.symbolicNames[i-1]
 	}
 
-	return strconv.Itoa(i - 1)
+	return d.vocabulary.GetDisplayName(i - 1)
 }
 
 func (d *DFASerializer) GetStateString(s *DFAState) string {
@@ -108,7 +100,7 @@ type LexerDFASerializer struct {
 }
 
 func NewLexerDFASerializer(dfa *DFA) *LexerDFASerializer {
-	return &LexerDFASerializer{DFASerializer: NewDFASerializer(dfa, nil, nil)}
+	return &LexerDFASerializer{DFASerializer: NewDFASerializer(dfa, nil)}
 }
 
 func (l *LexerDFASerializer) getEdgeLabel(i int) string {
@@ -0,0 +1,130 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Encoding identifies the character encoding of a byte source so it can be
+// decoded into code points before lexing.
+type Encoding int
+
+const (
+	// EncodingAuto detects UTF-8 and UTF-16 byte order marks and falls back
+	// to UTF-8 when there is none.
+	EncodingAuto Encoding = iota
+	EncodingUTF8
+	EncodingUTF16LE
+	EncodingUTF16BE
+	// EncodingLatin1 is ISO-8859-1, where every byte is the code point of
+	// the same value.
+	EncodingLatin1
+	// EncodingWindows1252 is Latin-1 with printable characters in place of
+	// the C1 control codes 0x80-0x9F.
+	EncodingWindows1252
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// ErrOddUTF16Length is returned when UTF-16 input has an odd number of bytes.
+var ErrOddUTF16Length = errors.New("UTF-16 input has an odd number of bytes")
+
+// windows1252 maps the bytes 0x80-0x9F to code points. Bytes that are
+// undefined in Windows-1252 keep their Latin-1 value.
+var windows1252 = [32]rune{
+	0x20AC, 0x0081, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0x008D, 0x017D, 0x008F,
+	0x0090, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0x009D, 0x017E, 0x0178,
+}
+
+func (e Encoding) String() string {
+	switch e {
+	case EncodingAuto:
+		return "auto"
+	case EncodingUTF8:
+		return "UTF-8"
+	case EncodingUTF16LE:
+		return "UTF-16LE"
+	case EncodingUTF16BE:
+		return "UTF-16BE"
+	case EncodingLatin1:
+		return "ISO-8859-1"
+	case EncodingWindows1252:
+		return "windows-1252"
+	}
+
+	return "Encoding(" + strconv.Itoa(int(e)) + ")"
+}
+
+// DetectEncoding returns the encoding announced by the byte order mark at the
+// start of data and the length of that mark. It returns EncodingUTF8 and 0
+// when data has no byte order mark.
+func DetectEncoding(data []byte) (Encoding, int) {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return EncodingUTF8, len(bomUTF8)
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return EncodingUTF16LE, len(bomUTF16LE)
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return EncodingUTF16BE, len(bomUTF16BE)
+	}
+
+	return EncodingUTF8, 0
+}
+
+// DecodeBytes decodes data in the given encoding into code points. A byte
+// order mark matching the encoding is stripped; with EncodingAuto the mark
+// also selects the encoding. Invalid UTF-8 and unpaired UTF-16 surrogates
+// decode to unicode.ReplacementChar.
+func DecodeBytes(data []byte, encoding Encoding) ([]rune, error) {
+	detected, bomLen := DetectEncoding(data)
+	if encoding == EncodingAuto {
+		encoding = detected
+	}
+	if encoding == detected {
+		data = data[bomLen:]
+	}
+
+	switch encoding {
+	case EncodingUTF8:
+		return bytes.Runes(data), nil
+
+	case EncodingUTF16LE, EncodingUTF16BE:
+		if len(data)%2 != 0 {
+			return nil, ErrOddUTF16Length
+		}
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			if encoding == EncodingUTF16LE {
+				units[i] = uint16(data[2*i]) | uint16(data[2*i+1])<<8
+			} else {
+				units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+			}
+		}
+		return utf16.Decode(units), nil
+
+	case EncodingLatin1, EncodingWindows1252:
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			if encoding == EncodingWindows1252 && b >= 0x80 && b <= 0x9F {
+				runes[i] = windows1252[b-0x80]
+			} else {
+				runes[i] = rune(b)
+			}
+		}
+		return runes, nil
+	}
+
+	return nil, errors.New("unsupported encoding " + encoding.String())
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeBytes(t *testing.T) {
+	assert := assertNew(t)
+
+	tests := []struct {
+		data     []byte
+		encoding Encoding
+		expected string
+	}{
+		{[]byte("h\xc3\xa9"), EncodingAuto, "hé"},
+		{[]byte("\xef\xbb\xbfh\xc3\xa9"), EncodingAuto, "hé"},
+		{[]byte("\xef\xbb\xbfh\xc3\xa9"), EncodingUTF8, "hé"},
+		{[]byte("\xff\xfeh\x00\xe9\x00=\xd8\x00\xde"), EncodingAuto, "hé😀"},
+		{[]byte("\xfe\xff\x00h\x00\xe9"), EncodingAuto, "hé"},
+		{[]byte("\x00h\x00\xe9"), EncodingUTF16BE, "hé"},
+		{[]byte("h\xe9\x80"), EncodingLatin1, "hé\u0080"},
+		{[]byte("h\xe9\x80\x96"), EncodingWindows1252, "hé€–"},
+	}
+	for _, test := range tests {
+		runes, err := DecodeBytes(test.data, test.encoding)
+		assert.Nil(err)
+		assert.Equal(test.expected, string(runes))
+	}
+
+	_, err := DecodeBytes([]byte("\xff\xfeh"), EncodingAuto)
+	assert.Equal(ErrOddUTF16Length, err)
+}
+
+func TestNewFileStreamWithEncoding(t *testing.T) {
+	assert := assertNew(t)
+
+	dir, err := ioutil.TempDir("", "antlr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "input.txt")
+	if err := ioutil.WriteFile(fileName, []byte("\xff\xfea\x00=\x00b\x00"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := NewFileStreamWithEncoding(fileName, EncodingAuto)
+	assert.Nil(err)
+	assert.Equal(3, fs.Size())
+	assert.Equal("a=b", fs.GetText(0, 2))
+	assert.Equal(fileName, fs.GetSourceName())
+}
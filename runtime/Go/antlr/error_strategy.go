@@ -11,12 +11,47 @@ import (
 	"strings"
 )
 
+// ErrorStrategy is the interface for defining strategies to deal with syntax
+// errors encountered during a parse by ANTLR-generated parsers. The parser
+// calls it in three situations:
+//
+// 1. The parser could not figure out which path to take in the ATN (none of
+// the available alternatives could possibly Match). Generated rule code
+// calls ReportError followed by Recover.
+//
+// 2. The current input does not Match what we were looking for.
+// BaseParser.Match and BaseParser.MatchWildcard call RecoverInline, which
+// must either return a Token to use in place of the missing one or panic
+// with a RecognitionException.
+//
+// 3. A sub-rule or loop is about to be entered. Generated code calls Sync,
+// which may consume tokens to resynchronize or panic with a
+// RecognitionException.
+//
+// Implementations may live outside package antlr. BaseParser relies on the
+// following contract:
+//
+// Reset is called from BaseParser.SetInputStream, SetTokenStream and reset
+// and must clear any recovery state.
+//
+// ReportMatch is called by BaseParser.Match and MatchWildcard each time a
+// token is Matched successfully, before the token is consumed. Strategies
+// use it to leave error recovery mode.
+//
+// InErrorRecoveryMode is called by BaseParser.Consume for every consumed
+// token when parse trees or parse listeners are in use. When it returns true
+// the token is added to the tree as an error node and listeners receive
+// VisitErrorNode; otherwise it becomes a terminal node.
+//
+// Tokens returned by RecoverInline with a token index of -1 are treated as
+// conjured up by the strategy and are added to the parse tree as error nodes
+// without being consumed.
 type ErrorStrategy interface {
-	reset(Parser)
+	Reset(Parser)
 	RecoverInline(Parser) Token
 	Recover(Parser, RecognitionException)
 	Sync(Parser)
-	inErrorRecoveryMode(Parser) bool
+	InErrorRecoveryMode(Parser) bool
 	ReportError(Parser, RecognitionException)
 	ReportMatch(Parser)
 }
@@ -40,7 +75,7 @@ func NewDefaultErrorStrategy() *DefaultErrorStrategy {
 	// error". This is used to suppress Reporting multiple error messages while
 	// attempting to recover from a detected syntax error.
 	//
-	// @see //inErrorRecoveryMode
+	// @see //InErrorRecoveryMode
 	//
 	d.errorRecoveryMode = false
 
@@ -57,7 +92,7 @@ func NewDefaultErrorStrategy() *DefaultErrorStrategy {
 
 // <p>The default implementation simply calls {@link //endErrorCondition} to
 // ensure that the handler is not in error recovery mode.</p>
-func (d *DefaultErrorStrategy) reset(recognizer Parser) {
+func (d *DefaultErrorStrategy) Reset(recognizer Parser) {
 	d.endErrorCondition(recognizer)
 }
 
@@ -71,7 +106,7 @@ func (d *DefaultErrorStrategy) beginErrorCondition(recognizer Parser) {
 	d.errorRecoveryMode = true
 }
 
-func (d *DefaultErrorStrategy) inErrorRecoveryMode(recognizer Parser) bool {
+func (d *DefaultErrorStrategy) InErrorRecoveryMode(recognizer Parser) bool {
 	return d.errorRecoveryMode
 }
 
@@ -118,7 +153,7 @@ func (d *DefaultErrorStrategy) ReportMatch(recognizer Parser) {
 func (d *DefaultErrorStrategy) ReportError(recognizer Parser, e RecognitionException) {
 	// if we've already Reported an error and have not Matched a token
 	// yet successfully, don't Report any errors.
-	if d.inErrorRecoveryMode(recognizer) {
+	if d.InErrorRecoveryMode(recognizer) {
 		return // don't Report spurious errors
 	}
 	d.beginErrorCondition(recognizer)
@@ -209,7 +244,7 @@ func (d *DefaultErrorStrategy) Recover(recognizer Parser, e RecognitionException
 //
 func (d *DefaultErrorStrategy) Sync(recognizer Parser) {
 	// If already recovering, don't try to Sync
-	if d.inErrorRecoveryMode(recognizer) {
+	if d.InErrorRecoveryMode(recognizer) {
 		return
 	}
 
@@ -275,7 +310,7 @@ func (d *DefaultErrorStrategy) ReportNoViableAlternative(recognizer Parser, e *N
 //
 func (this *DefaultErrorStrategy) ReportInputMisMatch(recognizer Parser, e *InputMisMatchException) {
 	msg := "mismatched input " + this.GetTokenErrorDisplay(e.offendingToken) +
-		" expecting " + e.getExpectedTokens().StringVerbose(recognizer.GetLiteralNames(), recognizer.GetSymbolicNames(), false)
+		" expecting " + e.getExpectedTokens().StringVerbose(recognizer.GetVocabulary(), false)
 	recognizer.NotifyErrorListeners(msg, e.offendingToken, e)
 }
 
@@ -312,7 +347,7 @@ func (d *DefaultErrorStrategy) ReportFailedPredicate(recognizer Parser, e *Faile
 // @param recognizer the parser instance
 //
 func (d *DefaultErrorStrategy) ReportUnwantedToken(recognizer Parser) {
-	if d.inErrorRecoveryMode(recognizer) {
+	if d.InErrorRecoveryMode(recognizer) {
 		return
 	}
 	d.beginErrorCondition(recognizer)
@@ -320,7 +355,7 @@ func (d *DefaultErrorStrategy) ReportUnwantedToken(recognizer Parser) {
 	tokenName := d.GetTokenErrorDisplay(t)
 	expecting := d.GetExpectedTokens(recognizer)
 	msg := "extraneous input " + tokenName + " expecting " +
-		expecting.StringVerbose(recognizer.GetLiteralNames(), recognizer.GetSymbolicNames(), false)
+		expecting.StringVerbose(recognizer.GetVocabulary(), false)
 	recognizer.NotifyErrorListeners(msg, t, nil)
 }
 
@@ -341,13 +376,13 @@ func (d *DefaultErrorStrategy) ReportUnwantedToken(recognizer Parser) {
 // @param recognizer the parser instance
 //
 func (d *DefaultErrorStrategy) ReportMissingToken(recognizer Parser) {
-	if d.inErrorRecoveryMode(recognizer) {
+	if d.InErrorRecoveryMode(recognizer) {
 		return
 	}
 	d.beginErrorCondition(recognizer)
 	t := recognizer.GetCurrentToken()
 	expecting := d.GetExpectedTokens(recognizer)
-	msg := "missing " + expecting.StringVerbose(recognizer.GetLiteralNames(), recognizer.GetSymbolicNames(), false) +
+	msg := "missing " + expecting.StringVerbose(recognizer.GetVocabulary(), false) +
 		" at " + d.GetTokenErrorDisplay(t)
 	recognizer.NotifyErrorListeners(msg, t, nil)
 }
@@ -517,12 +552,7 @@ func (d *DefaultErrorStrategy) GetMissingSymbol(recognizer Parser) Token {
 	if expectedTokenType == TokenEOF {
 		tokenText = "<missing EOF>"
 	} else {
-		ln := recognizer.GetLiteralNames()
-		if expectedTokenType > 0 && expectedTokenType < len(ln) {
-			tokenText = "<missing " + recognizer.GetLiteralNames()[expectedTokenType] + ">"
-		} else {
-			tokenText = "<missing undefined>" // TODO matches the JS impl
-		}
+		tokenText = "<missing " + recognizer.GetVocabulary().GetDisplayName(expectedTokenType) + ">"
 	}
 	current := currentSymbol
 	lookback := recognizer.GetTokenStream().LT(-1)
@@ -532,7 +562,7 @@ func (d *DefaultErrorStrategy) GetMissingSymbol(recognizer Parser) Token {
 
 	tf := recognizer.GetTokenFactory()
 
-	return tf.Create(current.GetSource(), expectedTokenType, tokenText, TokenDefaultChannel, -1, -1, current.GetLine(), current.GetColumn())
+	return tf.Create(current.GetSource(), expectedTokenType, tokenText, TokenDefaultChannel, -1, -1, current.GetLine(), current.GetColumn(), current.GetLine(), current.GetColumn())
 }
 
 func (d *DefaultErrorStrategy) GetExpectedTokens(recognizer Parser) *IntervalSet {
@@ -0,0 +1,95 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// recordingStrategy is an ErrorStrategy implemented from scratch outside
+// package antlr. It records the calls BaseParser makes and recovers from a
+// mismatched token by consuming it in error recovery mode.
+type recordingStrategy struct {
+	calls      []string
+	recovering bool
+}
+
+var _ antlr.ErrorStrategy = (*recordingStrategy)(nil)
+
+func (r *recordingStrategy) Reset(antlr.Parser) {
+	r.calls = append(r.calls, "Reset")
+	r.recovering = false
+}
+
+func (r *recordingStrategy) RecoverInline(p antlr.Parser) antlr.Token {
+	r.calls = append(r.calls, "RecoverInline")
+	r.recovering = true
+	return p.Consume()
+}
+
+func (r *recordingStrategy) Recover(antlr.Parser, antlr.RecognitionException) {
+	r.calls = append(r.calls, "Recover")
+}
+
+func (r *recordingStrategy) Sync(antlr.Parser) {
+	r.calls = append(r.calls, "Sync")
+}
+
+func (r *recordingStrategy) InErrorRecoveryMode(antlr.Parser) bool {
+	r.calls = append(r.calls, "InErrorRecoveryMode")
+	return r.recovering
+}
+
+func (r *recordingStrategy) ReportError(antlr.Parser, antlr.RecognitionException) {
+	r.calls = append(r.calls, "ReportError")
+}
+
+func (r *recordingStrategy) ReportMatch(antlr.Parser) {
+	r.calls = append(r.calls, "ReportMatch")
+	r.recovering = false
+}
+
+func TestCustomErrorStrategy(t *testing.T) {
+	strategy := new(recordingStrategy)
+	parser := antlr.NewBaseParser(nil)
+	parser.SetErrorHandler(strategy)
+	parser.SetTokenStream(antlr.NewCommonTokenStream(newWordSource("ab", "cde"), antlr.TokenDefaultChannel))
+
+	ctx := antlr.NewBaseParserRuleContext(nil, -1)
+	parser.EnterRule(ctx, 0, 0)
+	// the second ID is missing: ';' is Matched in error recovery mode
+	for _, ttype := range []int{tokenID, tokenID, tokenID, tokenSEMI, antlr.TokenEOF} {
+		parser.Match(ttype)
+	}
+	parser.ExitRule()
+
+	expected := []string{
+		"Reset",
+		"ReportMatch", "InErrorRecoveryMode", // ab
+		"RecoverInline", "InErrorRecoveryMode", // ;
+		"ReportMatch", "InErrorRecoveryMode", // cde
+		"ReportMatch", "InErrorRecoveryMode", // ;
+		"ReportMatch", "InErrorRecoveryMode", // EOF
+	}
+	if !reflect.DeepEqual(strategy.calls, expected) {
+		t.Errorf("calls = %q, want %q", strategy.calls, expected)
+	}
+
+	var errorNodes []string
+	for _, child := range ctx.GetChildren() {
+		if node, ok := child.(antlr.ErrorNode); ok {
+			errorNodes = append(errorNodes, node.GetText())
+		}
+	}
+	if !reflect.DeepEqual(errorNodes, []string{";"}) {
+		t.Errorf("error nodes = %q, want [\";\"]", errorNodes)
+	}
+	if n := ctx.GetChildCount(); n != 5 {
+		t.Errorf("rule has %d children, want 5", n)
+	}
+}
@@ -7,6 +7,7 @@ package antlr
 import (
 	"bytes"
 	"io"
+	"io/ioutil"
 	"os"
 )
 
@@ -44,6 +45,34 @@ func NewFileStream(fileName string) (*FileStream, error) {
 
 }
 
+// NewFileStreamWithEncoding loads fileName and decodes it from encoding into
+// code points. With EncodingAuto the encoding is taken from the file's byte
+// order mark, defaulting to UTF-8. A byte order mark matching the encoding is
+// not part of the stream.
+func NewFileStreamWithEncoding(fileName string, encoding Encoding) (*FileStream, error) {
+
+	buf, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := DecodeBytes(buf, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := new(FileStream)
+
+	fs.filename = fileName
+	fs.InputStream = &InputStream{
+		name: "<empty>",
+		data: data,
+		size: len(data),
+	}
+
+	return fs, nil
+}
+
 func (f *FileStream) GetSourceName() string {
 	return f.filename
 }
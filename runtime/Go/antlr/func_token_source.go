@@ -0,0 +1,94 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+// FuncTokenSource adapts a function returning successive tokens to the
+// TokenSource interface. The function must return an EOF token at the end of
+// input, and keep returning one if called again.
+//
+// The line, column and input stream reported by the source are derived from
+// the last token returned. The token factory is not used by the source
+// itself; it is kept for parsers that create tokens during error recovery.
+type FuncTokenSource struct {
+	next       func() Token
+	sourceName string
+	lastToken  Token
+	factory    TokenFactory
+}
+
+// NewFuncTokenSource creates a token source that calls next for each token.
+func NewFuncTokenSource(next func() Token) *FuncTokenSource {
+	return NewFuncTokenSourceWithName(next, "")
+}
+
+// NewFuncTokenSourceWithName creates a token source that calls next for each
+// token and reports sourceName from GetSourceName.
+func NewFuncTokenSourceWithName(next func() Token, sourceName string) *FuncTokenSource {
+	return &FuncTokenSource{
+		next:       next,
+		sourceName: sourceName,
+		factory:    CommonTokenFactoryDEFAULT,
+	}
+}
+
+func (f *FuncTokenSource) NextToken() Token {
+	f.lastToken = f.next()
+	return f.lastToken
+}
+
+// Skip does nothing; the function decides which tokens to return.
+func (f *FuncTokenSource) Skip() {}
+
+// More does nothing; the function decides which tokens to return.
+func (f *FuncTokenSource) More() {}
+
+// GetLine returns the line just after the last token returned.
+func (f *FuncTokenSource) GetLine() int {
+	if f.lastToken == nil {
+		return 1
+	}
+
+	return f.lastToken.GetEndLine()
+}
+
+// GetCharPositionInLine returns the column just after the last token
+// returned.
+func (f *FuncTokenSource) GetCharPositionInLine() int {
+	if f.lastToken == nil {
+		return 0
+	}
+
+	return f.lastToken.GetEndColumn()
+}
+
+// GetInputStream returns the input stream of the last token returned, or nil
+// if there is none.
+func (f *FuncTokenSource) GetInputStream() CharStream {
+	if f.lastToken == nil {
+		return nil
+	}
+
+	return tokenInputStream(f.lastToken)
+}
+
+func (f *FuncTokenSource) GetSourceName() string {
+	if f.sourceName != "" {
+		return f.sourceName
+	}
+
+	if input := f.GetInputStream(); input != nil {
+		return input.GetSourceName()
+	}
+
+	return "<unknown>"
+}
+
+func (f *FuncTokenSource) SetTokenFactory(factory TokenFactory) {
+	f.factory = factory
+}
+
+func (f *FuncTokenSource) GetTokenFactory() TokenFactory {
+	return f.factory
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr_test
+
+import (
+	"testing"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+const (
+	tokenID   = 1
+	tokenSEMI = 2
+)
+
+// wordSource is a TokenSource implemented outside package antlr. It returns
+// one ID token per word of its input, separated by ';' tokens, then EOF.
+type wordSource struct {
+	input   antlr.CharStream
+	factory antlr.TokenFactory
+	words   []string
+	next    int
+	index   int
+}
+
+func newWordSource(words ...string) *wordSource {
+	text := ""
+	for _, word := range words {
+		text += word + ";"
+	}
+	return &wordSource{
+		input:   antlr.NewInputStream(text),
+		factory: antlr.CommonTokenFactoryDEFAULT,
+		words:   words,
+	}
+}
+
+func (w *wordSource) NextToken() antlr.Token {
+	source := antlr.NewTokenSourceCharStreamPair(w, w.input)
+
+	if w.next >= 2*len(w.words) {
+		return w.factory.Create(source, antlr.TokenEOF, "", antlr.TokenDefaultChannel, w.index, w.index-1, 1, w.index, 1, w.index)
+	}
+
+	ttype, length := tokenSEMI, 1
+	if w.next%2 == 0 {
+		ttype, length = tokenID, len(w.words[w.next/2])
+	}
+	w.next++
+	start := w.index
+	w.index += length
+	return w.factory.Create(source, ttype, "", antlr.TokenDefaultChannel, start, w.index-1, 1, start, 1, w.index)
+}
+
+func (w *wordSource) Skip()                                {}
+func (w *wordSource) More()                                {}
+func (w *wordSource) GetLine() int                         { return 1 }
+func (w *wordSource) GetCharPositionInLine() int           { return w.index }
+func (w *wordSource) GetInputStream() antlr.CharStream     { return w.input }
+func (w *wordSource) GetSourceName() string                { return "words" }
+func (w *wordSource) SetTokenFactory(f antlr.TokenFactory) { w.factory = f }
+func (w *wordSource) GetTokenFactory() antlr.TokenFactory  { return w.factory }
+
+var _ antlr.TokenSource = (*wordSource)(nil)
+
+func TestExternalTokenSource(t *testing.T) {
+	stream := antlr.NewCommonTokenStream(newWordSource("ab", "cde"), antlr.TokenDefaultChannel)
+	stream.Fill()
+
+	if n := len(stream.GetAllTokens()); n != 5 {
+		t.Errorf("got %d tokens, want 5", n)
+	}
+	if text := stream.GetAllText(); text != "ab;cde;" {
+		t.Errorf("text = %q, want %q", text, "ab;cde;")
+	}
+
+	// a parser reads the tokens like those of a generated lexer
+	stream = antlr.NewCommonTokenStream(newWordSource("ab", "cde"), antlr.TokenDefaultChannel)
+	parser := antlr.NewBaseParser(stream)
+	ctx := antlr.NewBaseParserRuleContext(nil, -1)
+	parser.EnterRule(ctx, 0, 0)
+	for _, ttype := range []int{tokenID, tokenSEMI, tokenID, tokenSEMI, antlr.TokenEOF} {
+		parser.Match(ttype)
+	}
+	parser.ExitRule()
+
+	if n := ctx.GetChildCount(); n != 5 {
+		t.Errorf("rule has %d children, want 5", n)
+	}
+	if text := ctx.GetText(); text != "ab;cde;<EOF>" {
+		t.Errorf("rule text = %q, want %q", text, "ab;cde;<EOF>")
+	}
+	if name := parser.GetTokenStream().GetTokenSource().GetSourceName(); name != "words" {
+		t.Errorf("source name = %q, want %q", name, "words")
+	}
+}
+
+func TestFuncTokenSource(t *testing.T) {
+	input := antlr.NewInputStream("ab\ncd;")
+	var source *antlr.FuncTokenSource
+	tokens := []antlr.Token{
+		antlr.CommonTokenFactoryDEFAULT.Create(antlr.NewTokenSourceCharStreamPair(nil, input), tokenID, "", antlr.TokenDefaultChannel, 0, 4, 1, 0, 2, 2),
+		antlr.CommonTokenFactoryDEFAULT.Create(antlr.NewTokenSourceCharStreamPair(nil, input), tokenSEMI, "", antlr.TokenDefaultChannel, 5, 5, 2, 2, 2, 3),
+	}
+	next := 0
+	source = antlr.NewFuncTokenSource(func() antlr.Token {
+		if next < len(tokens) {
+			next++
+			return tokens[next-1]
+		}
+		return antlr.CommonTokenFactoryDEFAULT.Create(antlr.NewTokenSourceCharStreamPair(source, input), antlr.TokenEOF, "", antlr.TokenDefaultChannel, 6, 5, 2, 3, 2, 3)
+	})
+
+	if line, column := source.GetLine(), source.GetCharPositionInLine(); line != 1 || column != 0 {
+		t.Errorf("position before the first token = %d:%d, want 1:0", line, column)
+	}
+	if name := source.GetSourceName(); name != "<unknown>" {
+		t.Errorf("source name before the first token = %q, want %q", name, "<unknown>")
+	}
+
+	source.NextToken()
+	// the multi-line token "ab\ncd" ends on line 2 before column 2
+	if line, column := source.GetLine(), source.GetCharPositionInLine(); line != 2 || column != 2 {
+		t.Errorf("position after %q = %d:%d, want 2:2", tokens[0].GetText(), line, column)
+	}
+	if name := source.GetSourceName(); name != input.GetSourceName() {
+		t.Errorf("source name = %q, want the input's %q", name, input.GetSourceName())
+	}
+
+	stream := antlr.NewCommonTokenStream(source, antlr.TokenDefaultChannel)
+	stream.Fill()
+	all := stream.GetAllTokens()
+	if n := len(all); n != 2 || all[n-1].GetTokenType() != antlr.TokenEOF {
+		t.Errorf("stream tokens = %v, want ';' and EOF", all)
+	}
+	// EOF keeps being returned
+	if ttype := source.NextToken().GetTokenType(); ttype != antlr.TokenEOF {
+		t.Errorf("token after EOF has type %d", ttype)
+	}
+	if line, column := source.GetLine(), source.GetCharPositionInLine(); line != 2 || column != 3 {
+		t.Errorf("position at EOF = %d:%d, want 2:3", line, column)
+	}
+
+	named := antlr.NewFuncTokenSourceWithName(func() antlr.Token { return tokens[0] }, "gen.txt")
+	named.NextToken()
+	if name := named.GetSourceName(); name != "gen.txt" {
+		t.Errorf("source name = %q, want %q", name, "gen.txt")
+	}
+}
+
+func TestFuncTokenSourceEndPosition(t *testing.T) {
+	// the lexer that made the token treated '\r' as a line break and
+	// expanded the tab to column 4; the source reports its end position
+	// rather than one derived from the text
+	input := antlr.NewInputStream("a\r\tb")
+	token := antlr.CommonTokenFactoryDEFAULT.Create(antlr.NewTokenSourceCharStreamPair(nil, input), tokenID, "", antlr.TokenDefaultChannel, 0, 3, 1, 0, 2, 5)
+	source := antlr.NewFuncTokenSource(func() antlr.Token { return token })
+	source.NextToken()
+
+	if line, column := source.GetLine(), source.GetCharPositionInLine(); line != 2 || column != 5 {
+		t.Errorf("position after the token = %d:%d, want 2:5", line, column)
+	}
+}
@@ -9,6 +9,9 @@ type InputStream struct {
 	index int
 	data  []rune
 	size  int
+
+	// lineIndex is built on first use by the position lookup methods.
+	lineIndex *LineIndex
 }
 
 func NewInputStream(data string) *InputStream {
@@ -108,6 +111,39 @@ func (*InputStream) GetSourceName() string {
 	return "Obtained from string"
 }
 
+// GetLineIndex returns the line table of the stream, building it on first
+// use.
+func (is *InputStream) GetLineIndex() *LineIndex {
+	if is.lineIndex == nil {
+		is.lineIndex = newLineIndex(is.data, nil)
+	}
+
+	return is.lineIndex
+}
+
+// PositionOf returns the line (1..n) and column (0..n-1) of the character at
+// index, for example the start of a token or of a RecognitionException's
+// offending token.
+func (is *InputStream) PositionOf(index int) (line, column int) {
+	return is.GetLineIndex().PositionOf(index)
+}
+
+// OffsetOf returns the index of the character at line and column, or -1 if
+// the line does not exist.
+func (is *InputStream) OffsetOf(line, column int) int {
+	return is.GetLineIndex().OffsetOf(line, column)
+}
+
+// LineText returns the text of line n without its line terminator, for
+// showing source snippets in diagnostics.
+func (is *InputStream) LineText(n int) string {
+	return is.GetLineIndex().LineText(n)
+}
+
+func (is *InputStream) runes() []rune {
+	return is.data
+}
+
 func (is *InputStream) String() string {
 	return string(is.data)
 }
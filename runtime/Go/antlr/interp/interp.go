@@ -0,0 +1,219 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+// Package interp reads the .interp files written by the ANTLR tool next to
+// the generated code of a grammar. An .interp file holds everything needed to
+// run the grammar without generated code: the token names, the rule, channel
+// and mode names and the serialized ATN.
+//
+// A loaded Grammar creates lexer and parser interpreters for its ATN:
+//
+//	lexerGrammar, err := interp.Load("ExprLexer.interp")
+//	...
+//	parserGrammar, err := interp.Load("Expr.interp")
+//	...
+//	lexer := lexerGrammar.NewLexerInterpreter(antlr.NewInputStream(src))
+//	parser := parserGrammar.NewParserInterpreter(antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel))
+//	tree := parser.Parse(parserGrammar.RuleIndex("expr"))
+package interp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// The section headers of an .interp file, in the order the tool writes them.
+// Channel and mode names are only present for lexer grammars.
+const (
+	sectionLiteralNames  = "token literal names:"
+	sectionSymbolicNames = "token symbolic names:"
+	sectionRuleNames     = "rule names:"
+	sectionChannelNames  = "channel names:"
+	sectionModeNames     = "mode names:"
+	sectionATN           = "atn:"
+)
+
+// Grammar describes a grammar loaded from an .interp file.
+type Grammar struct {
+	// Name is the name of the grammar, taken from the file name by Load.
+	Name string
+
+	Vocabulary   antlr.Vocabulary
+	RuleNames    []string
+	ChannelNames []string // nil for parser grammars
+	ModeNames    []string // nil for parser grammars
+	ATN          *antlr.ATN
+}
+
+// Load reads the .interp file fileName. The grammar is named after the file,
+// so "ExprLexer.interp" yields a Grammar named "ExprLexer".
+func Load(fileName string) (*Grammar, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fileName, err)
+	}
+	g.Name = strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+
+	return g, nil
+}
+
+// Parse reads a grammar in .interp format from r.
+func Parse(r io.Reader) (*Grammar, error) {
+	var literalNames, symbolicNames []string
+	var serializedATN []uint16
+	seen := make(map[string]bool)
+	g := new(Grammar)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<30) // the ATN is a single, possibly very long, line
+	lineNumber := 0
+	nextLine := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		lineNumber++
+		return strings.TrimRight(scanner.Text(), "\r"), true
+	}
+
+	for {
+		header, ok := nextLine()
+		if !ok {
+			break
+		}
+		if header == "" {
+			continue
+		}
+		headerLine := lineNumber
+		if seen[header] {
+			return nil, fmt.Errorf("line %d: duplicate section %q", lineNumber, header)
+		}
+		seen[header] = true
+
+		if header == sectionATN {
+			line, ok := nextLine()
+			if !ok {
+				return nil, fmt.Errorf("line %d: missing ATN", lineNumber)
+			}
+			var err error
+			if serializedATN, err = parseATN(line); err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+			}
+			continue
+		}
+
+		var names []string
+		for {
+			line, ok := nextLine()
+			if !ok || line == "" {
+				break
+			}
+			if line == "null" {
+				line = ""
+			}
+			names = append(names, line)
+		}
+
+		switch header {
+		case sectionLiteralNames:
+			literalNames = names
+		case sectionSymbolicNames:
+			symbolicNames = names
+		case sectionRuleNames:
+			g.RuleNames = names
+		case sectionChannelNames:
+			g.ChannelNames = names
+		case sectionModeNames:
+			g.ModeNames = names
+		default:
+			return nil, fmt.Errorf("line %d: unknown section %q", headerLine, header)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, header := range []string{sectionLiteralNames, sectionSymbolicNames, sectionRuleNames, sectionATN} {
+		if !seen[header] {
+			return nil, fmt.Errorf("missing section %q", header)
+		}
+	}
+
+	atn, err := deserializeATN(serializedATN)
+	if err != nil {
+		return nil, err
+	}
+	g.ATN = atn
+	g.Vocabulary = antlr.NewVocabulary(literalNames, symbolicNames)
+
+	return g, nil
+}
+
+// parseATN parses the serialized ATN, written as a list of integers such as
+// "[3, 24715, 42794, ...]".
+func parseATN(line string) ([]uint16, error) {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "[")
+	line = strings.TrimSuffix(line, "]")
+
+	elements := strings.Split(line, ",")
+	data := make([]uint16, len(elements))
+	for i, element := range elements {
+		value, err := strconv.ParseUint(strings.TrimSpace(element), 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ATN element %q", element)
+		}
+		data[i] = uint16(value)
+	}
+
+	return data, nil
+}
+
+// deserializeATN turns the panics of the ATN deserializer on malformed data
+// into an error.
+func deserializeATN(data []uint16) (atn *antlr.ATN, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid ATN: %v", r)
+		}
+	}()
+
+	return antlr.NewATNDeserializer(nil).DeserializeFromUInt16(data), nil
+}
+
+// RuleIndex returns the index of the rule named ruleName, or -1 if the
+// grammar has no such rule.
+func (g *Grammar) RuleIndex(ruleName string) int {
+	for i, name := range g.RuleNames {
+		if name == ruleName {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// NewLexerInterpreter creates a lexer for input running the grammar's ATN.
+// It panics if the grammar is not a lexer grammar.
+func (g *Grammar) NewLexerInterpreter(input antlr.CharStream) *antlr.LexerInterpreter {
+	return antlr.NewLexerInterpreter(g.Name, g.Vocabulary, g.RuleNames, g.ChannelNames, g.ModeNames, g.ATN, input)
+}
+
+// NewParserInterpreter creates a parser for input running the grammar's ATN.
+// It panics if the grammar is not a parser grammar.
+func (g *Grammar) NewParserInterpreter(input antlr.TokenStream) *antlr.ParserInterpreter {
+	return antlr.NewParserInterpreter(g.Name, g.Vocabulary, g.RuleNames, g.ATN, input)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package interp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+func TestLoadLexer(t *testing.T) {
+	g, err := Load("testdata/LexerB.interp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Name != "LexerB" {
+		t.Errorf("Name = %q, want %q", g.Name, "LexerB")
+	}
+	if want := []string{"ID", "INT", "SEMI", "ASSIGN", "PLUS", "MULT", "WS"}; !reflect.DeepEqual(g.RuleNames, want) {
+		t.Errorf("RuleNames = %q, want %q", g.RuleNames, want)
+	}
+	if want := []string{"DEFAULT_TOKEN_CHANNEL", "HIDDEN"}; !reflect.DeepEqual(g.ChannelNames, want) {
+		t.Errorf("ChannelNames = %q, want %q", g.ChannelNames, want)
+	}
+	if want := []string{"DEFAULT_MODE"}; !reflect.DeepEqual(g.ModeNames, want) {
+		t.Errorf("ModeNames = %q, want %q", g.ModeNames, want)
+	}
+	if name := g.Vocabulary.GetDisplayName(4); name != "'='" {
+		t.Errorf("display name of token 4 = %q, want %q", name, "'='")
+	}
+	if name := g.Vocabulary.GetSymbolicName(1); name != "ID" {
+		t.Errorf("symbolic name of token 1 = %q, want %q", name, "ID")
+	}
+
+	var types []int
+	for _, token := range g.NewLexerInterpreter(antlr.NewInputStream("a=12;")).GetAllTokens() {
+		types = append(types, token.GetTokenType())
+	}
+	if want := []int{1, 4, 2, 3}; !reflect.DeepEqual(types, want) {
+		t.Errorf("token types = %v, want %v", types, want)
+	}
+}
+
+func TestLoadParser(t *testing.T) {
+	lexerGrammar, err := Load("testdata/LexerB.interp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := Load("testdata/ParserP.interp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.ChannelNames != nil || g.ModeNames != nil {
+		t.Errorf("parser grammar has channel names %q and mode names %q", g.ChannelNames, g.ModeNames)
+	}
+	if i := g.RuleIndex("expr"); i != 2 {
+		t.Errorf("RuleIndex(expr) = %d, want 2", i)
+	}
+	if i := g.RuleIndex("missing"); i != -1 {
+		t.Errorf("RuleIndex(missing) = %d, want -1", i)
+	}
+
+	lexer := lexerGrammar.NewLexerInterpreter(antlr.NewInputStream("a=1+2;"))
+	parser := g.NewParserInterpreter(antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel))
+	tree := parser.Parse(g.RuleIndex("s"))
+
+	want := "(s (stat a = (expr (expr 1) + (expr 2)) ;) <EOF>)"
+	if s := tree.ToStringTree(nil, parser); s != want {
+		t.Errorf("tree = %s, want %s", s, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name, input, err string
+	}{
+		{"unknown section", "rule names:\na\n\ngrammar:\nx\n", `line 4: unknown section "grammar:"`},
+		{"duplicate section", "rule names:\na\n\nrule names:\nb\n", `line 4: duplicate section "rule names:"`},
+		{"missing section", "rule names:\na\n\natn:\n[3]\n", `missing section "token literal names:"`},
+		{"missing ATN", "atn:\n", "line 1: missing ATN"},
+		{"invalid element", "atn:\n[3, x, 4]\n", `line 2: invalid ATN element " x"`},
+		{"element out of range", "atn:\n[65536]\n", `line 2: invalid ATN element "65536"`},
+		{"invalid ATN", "token literal names:\n\ntoken symbolic names:\n\nrule names:\n\natn:\n[2]\n", "invalid ATN: "},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(strings.NewReader(test.input))
+		if err == nil || !strings.HasPrefix(err.Error(), test.err) {
+			t.Errorf("%s: error = %v, want %s", test.name, err, test.err)
+		}
+	}
+}
@@ -207,15 +207,17 @@ func (i *IntervalSet) removeOne(v int) {
 }
 
 func (i *IntervalSet) String() string {
-	return i.StringVerbose(nil, nil, false)
+	return i.StringVerbose(nil, false)
 }
 
-func (i *IntervalSet) StringVerbose(literalNames []string, symbolicNames []string, elemsAreChar bool) string {
+// StringVerbose returns the set as token names from vocabulary if it is not
+// nil, as quoted characters if elemsAreChar is set, or else as numbers.
+func (i *IntervalSet) StringVerbose(vocabulary Vocabulary, elemsAreChar bool) string {
 
 	if i.intervals == nil {
 		return "{}"
-	} else if literalNames != nil || symbolicNames != nil {
-		return i.toTokenString(literalNames, symbolicNames)
+	} else if vocabulary != nil {
+		return i.toTokenString(vocabulary)
 	} else if elemsAreChar {
 		return i.toCharString()
 	}
@@ -267,11 +269,11 @@ func (i *IntervalSet) toIndexString() string {
 	return names[0]
 }
 
-func (i *IntervalSet) toTokenString(literalNames []string, symbolicNames []string) string {
+func (i *IntervalSet) toTokenString(vocabulary Vocabulary) string {
 	names := make([]string, 0)
 	for _, v := range i.intervals {
 		for j := v.Start; j < v.Stop; j++ {
-			names = append(names, i.elementName(literalNames, symbolicNames, j))
+			names = append(names, i.elementName(vocabulary, j))
 		}
 	}
 	if len(names) > 1 {
@@ -281,16 +283,12 @@ func (i *IntervalSet) toTokenString(literalNames []string, symbolicNames []strin
 	return names[0]
 }
 
-func (i *IntervalSet) elementName(literalNames []string, symbolicNames []string, a int) string {
+func (i *IntervalSet) elementName(vocabulary Vocabulary, a int) string {
 	if a == TokenEOF {
 		return "<EOF>"
 	} else if a == TokenEpsilon {
 		return "<EPSILON>"
-	} else {
-		if a < len(literalNames) && literalNames[a] != "" {
-			return literalNames[a]
-		}
-
-		return symbolicNames[a]
 	}
+
+	return vocabulary.GetDisplayName(a)
 }
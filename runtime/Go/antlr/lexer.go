@@ -40,8 +40,11 @@ type BaseLexer struct {
 
 	input                  CharStream
 	factory                TokenFactory
+	errHandler             LexerErrorStrategy
 	tokenFactorySourcePair *TokenSourceCharStreamPair
+	segmentSourcePair      *TokenSourceCharStreamPair
 	token                  Token
+	pendingTokens          []Token
 	hitEOF                 bool
 	channel                int
 	thetype                int
@@ -55,9 +58,16 @@ func NewBaseLexer(input CharStream) *BaseLexer {
 	lexer := new(BaseLexer)
 
 	lexer.BaseRecognizer = NewBaseRecognizer()
+	lexer.grammarATN = func() *ATN {
+		if lexer.Interpreter == nil {
+			return nil
+		}
+		return lexer.Interpreter.ATN()
+	}
 
 	lexer.input = input
 	lexer.factory = CommonTokenFactoryDEFAULT
+	lexer.errHandler = NewDefaultLexerErrorStrategy()
 	lexer.tokenFactorySourcePair = &TokenSourceCharStreamPair{lexer, input}
 
 	lexer.Virt = lexer
@@ -73,6 +83,10 @@ func NewBaseLexer(input CharStream) *BaseLexer {
 	// emit another token.
 	lexer.token = nil
 
+	// Tokens queued by EmitTokens, returned by NextToken before it Matches
+	// more input.
+	lexer.pendingTokens = nil
+
 	// What character index in the stream did the current token start at?
 	// Needed, for example, to get the text for current token. Set at
 	// the start of NextToken.
@@ -124,6 +138,7 @@ func (b *BaseLexer) reset() {
 		b.input.Seek(0) // rewind the input
 	}
 	b.token = nil
+	b.pendingTokens = nil
 	b.thetype = TokenInvalidType
 	b.channel = TokenDefaultChannel
 	b.TokenStartCharIndex = -1
@@ -146,6 +161,18 @@ func (b *BaseLexer) GetInputStream() CharStream {
 	return b.input
 }
 
+// GetTokenStartLine returns the line of the first character of the token
+// being Matched.
+func (b *BaseLexer) GetTokenStartLine() int {
+	return b.TokenStartLine
+}
+
+// GetTokenStartColumn returns the column of the first character of the token
+// being Matched.
+func (b *BaseLexer) GetTokenStartColumn() int {
+	return b.TokenStartColumn
+}
+
 func (b *BaseLexer) GetSourceName() string {
 	return b.GrammarFileName
 }
@@ -158,17 +185,28 @@ func (b *BaseLexer) GetTokenFactory() TokenFactory {
 	return b.factory
 }
 
-func (b *BaseLexer) setTokenFactory(f TokenFactory) {
+func (b *BaseLexer) SetTokenFactory(f TokenFactory) {
 	b.factory = f
 }
 
+// GetErrorHandler returns the strategy deciding what happens with input no
+// lexer rule Matches.
+func (b *BaseLexer) GetErrorHandler() LexerErrorStrategy {
+	return b.errHandler
+}
+
+// SetErrorHandler sets the strategy deciding what happens with input no lexer
+// rule Matches. The default, DefaultLexerErrorStrategy, drops it.
+func (b *BaseLexer) SetErrorHandler(e LexerErrorStrategy) {
+	b.errHandler = e
+}
+
 func (b *BaseLexer) safeMatch() (ret int) {
 	defer func() {
 		if e := recover(); e != nil {
 			if re, ok := e.(RecognitionException); ok {
-				b.notifyListeners(re) // Report error
-				b.Recover(re)
-				ret = LexerSkip // default
+				b.errHandler.ReportError(b, re)
+				ret = b.errHandler.Recover(b, re)
 			}
 		}
 	}()
@@ -191,7 +229,19 @@ func (b *BaseLexer) NextToken() Token {
 		b.input.Release(tokenStartMarker)
 	}()
 
+	// The end of an incomplete stream is not the end of input, so hitEOF is
+	// only set once it is closed and the simulator matches EOF itself.
+	in, incomplete := b.input.(incompleteCharStream)
+	incomplete = incomplete && !in.IsClosed()
+
 	for {
+		// tokens queued by EmitTokens come before any more input is Matched
+		if len(b.pendingTokens) > 0 {
+			b.token = b.pendingTokens[0]
+			b.pendingTokens[0] = nil
+			b.pendingTokens = b.pendingTokens[1:]
+			return b.token
+		}
 		if b.hitEOF {
 			b.EmitEOF()
 			return b.token
@@ -202,14 +252,31 @@ func (b *BaseLexer) NextToken() Token {
 		b.TokenStartColumn = b.Interpreter.GetCharPositionInLine()
 		b.TokenStartLine = b.Interpreter.GetLine()
 		b.text = ""
+		startMode, startModeStack := b.mode, b.modeStack
+		if incomplete {
+			startModeStack = append(IntStack(nil), b.modeStack...)
+		}
 		continueOuter := false
+		queued := 0
 		for {
 			b.thetype = TokenInvalidType
 			ttype := LexerSkip
 
+			queued = len(b.pendingTokens)
 			ttype = b.safeMatch()
 
-			if b.input.LA(1) == TokenEOF {
+			if ttype == TokenNeedMoreInput {
+				// Rewind so the token is matched again from its start,
+				// including any More() fragments, once text is appended.
+				// The actions of those fragments then run again.
+				b.input.Seek(b.TokenStartCharIndex)
+				if p, ok := b.Interpreter.(positionSetter); ok {
+					p.SetPosition(b.TokenStartLine, b.TokenStartColumn)
+				}
+				b.mode, b.modeStack = startMode, startModeStack
+				return b.emitNeedMoreInput()
+			}
+			if !incomplete && b.input.LA(1) == TokenEOF {
 				b.hitEOF = true
 			}
 			if b.thetype == TokenInvalidType {
@@ -227,9 +294,13 @@ func (b *BaseLexer) NextToken() Token {
 		if continueOuter {
 			continue
 		}
-		if b.token == nil {
+		if b.token == nil && len(b.pendingTokens) == queued {
 			b.Virt.Emit()
 		}
+		if b.token == nil {
+			// the last Match emitted its tokens with EmitTokens only
+			continue
+		}
 		return b.token
 	}
 
@@ -290,15 +361,47 @@ func (b *BaseLexer) GetTokenSourceCharStreamPair() *TokenSourceCharStreamPair {
 	return b.tokenFactorySourcePair
 }
 
-// By default does not support multiple emits per NextToken invocation
-// for efficiency reasons. Subclass and override l method, NextToken,
-// and GetToken (to push tokens into a list and pull from that list
-// rather than a single variable as l implementation does).
-// /
+// tokenSourcePair returns the pair to create a token starting at index with.
+// Over input made of named segments its input stream is the segment the
+// token starts in.
+func (b *BaseLexer) tokenSourcePair(index int) *TokenSourceCharStreamPair {
+	segmented, ok := b.input.(segmentedCharStream)
+	if !ok {
+		return b.tokenFactorySourcePair
+	}
+
+	input := segmented.segmentStream(index)
+	if b.segmentSourcePair == nil || b.segmentSourcePair.charStream != input {
+		b.segmentSourcePair = &TokenSourceCharStreamPair{b, input}
+	}
+	return b.segmentSourcePair
+}
+
+// EmitToken sets the token NextToken returns for the current Match,
+// replacing any token emitted before. Use EmitTokens to emit more than one.
 func (b *BaseLexer) EmitToken(token Token) {
 	b.token = token
 }
 
+// EmitTokens queues tokens to be returned by NextToken, in order, before any
+// more input is Matched. They follow the token set with Emit or EmitToken
+// for the current Match, if any. A Match that queues tokens does not emit
+// its own token automatically, and the tokens are returned even if the rule
+// goes on to Skip or More. Tokens queued by a rule that calls More are
+// returned after the token the following rules complete, which is emitted
+// as usual. Lexer actions use it to split a '>>' into two '>' tokens:
+//
+//	l.EmitTokens(gt1, gt2)
+//
+// or to follow a NEWLINE token with the DEDENT tokens of an
+// indentation-sensitive language:
+//
+//	l.Emit()
+//	l.EmitTokens(dedent1, dedent2)
+func (b *BaseLexer) EmitTokens(tokens ...Token) {
+	b.pendingTokens = append(b.pendingTokens, tokens...)
+}
+
 // The standard method called to automatically emit a token at the
 // outermost lexical rule. The token object should point into the
 // char buffer start..stop. If there is a text override in 'text',
@@ -306,7 +409,7 @@ func (b *BaseLexer) EmitToken(token Token) {
 // custom Token objects or provide a Newfactory.
 // /
 func (b *BaseLexer) Emit() Token {
-	t := b.factory.Create(b.tokenFactorySourcePair, b.thetype, b.text, b.channel, b.TokenStartCharIndex, b.GetCharIndex()-1, b.TokenStartLine, b.TokenStartColumn)
+	t := b.factory.Create(b.tokenSourcePair(b.TokenStartCharIndex), b.thetype, b.text, b.channel, b.TokenStartCharIndex, b.GetCharIndex()-1, b.TokenStartLine, b.TokenStartColumn, b.GetLine(), b.GetCharPositionInLine())
 	b.EmitToken(t)
 	return t
 }
@@ -314,11 +417,33 @@ func (b *BaseLexer) Emit() Token {
 func (b *BaseLexer) EmitEOF() Token {
 	cpos := b.GetCharPositionInLine()
 	lpos := b.GetLine()
-	eof := b.factory.Create(b.tokenFactorySourcePair, TokenEOF, "", TokenDefaultChannel, b.input.Index(), b.input.Index()-1, lpos, cpos)
+	eof := b.factory.Create(b.tokenSourcePair(b.input.Index()), TokenEOF, "", TokenDefaultChannel, b.input.Index(), b.input.Index()-1, lpos, cpos, lpos, cpos)
 	b.EmitToken(eof)
 	return eof
 }
 
+func (b *BaseLexer) emitNeedMoreInput() Token {
+	start := b.TokenStartCharIndex
+	t := b.factory.Create(b.tokenSourcePair(start), TokenNeedMoreInput, "", TokenDefaultChannel, start, start-1, b.TokenStartLine, b.TokenStartColumn, b.TokenStartLine, b.TokenStartColumn)
+	b.EmitToken(t)
+	return t
+}
+
+// SetPositionTracking configures how the lexer advances the line and column
+// of its position: the tab stop width, the characters that end a line and
+// whether "\r\n" is a single line break. Passing nil restores the default,
+// where only '\n' ends a line and every character is one column wide.
+//
+// It panics if the lexer's Interpreter is not a LexerATNSimulator and does
+// not provide SetPositionTracking either.
+func (b *BaseLexer) SetPositionTracking(tracking *PositionTracking) {
+	t, ok := b.Interpreter.(positionTracker)
+	if !ok {
+		panic("The lexer's ATN simulator does not support position tracking.")
+	}
+	t.SetPositionTracking(tracking)
+}
+
 func (b *BaseLexer) GetCharPositionInLine() int {
 	return b.Interpreter.GetCharPositionInLine()
 }
@@ -359,28 +484,35 @@ func (b *BaseLexer) GetATN() *ATN {
 }
 
 // Return a list of all Token objects in input char stream.
-// Forces load of all tokens. Does not include EOF token.
+// Forces load of all tokens. Does not include EOF token. Over an
+// AppendableInputStream that is still open, it stops at the first token
+// that needs more input.
 // /
 func (b *BaseLexer) GetAllTokens() []Token {
 	vl := b.Virt
 	tokens := make([]Token, 0)
 	t := vl.NextToken()
-	for t.GetTokenType() != TokenEOF {
+	for t.GetTokenType() != TokenEOF && t.GetTokenType() != TokenNeedMoreInput {
 		tokens = append(tokens, t)
 		t = vl.NextToken()
 	}
 	return tokens
 }
 
-func (b *BaseLexer) notifyListeners(e RecognitionException) {
-	start := b.TokenStartCharIndex
-	stop := b.input.Index()
-	text := b.input.GetTextFromInterval(NewInterval(start, stop))
-	msg := "token recognition error at: '" + text + "'"
+// NotifyListeners reports e, raised while Matching the current token, to the
+// lexer's error listeners.
+func (b *BaseLexer) NotifyListeners(e RecognitionException) {
+	msg := "token recognition error at: '" + b.GetErrorText() + "'"
 	listener := b.GetErrorListenerDispatch()
 	listener.SyntaxError(b, nil, b.TokenStartLine, b.TokenStartColumn, msg, e)
 }
 
+// GetErrorText returns the text of the token being Matched up to and
+// including the character at which Matching failed.
+func (b *BaseLexer) GetErrorText() string {
+	return b.input.GetTextFromInterval(NewInterval(b.TokenStartCharIndex, b.input.Index()))
+}
+
 func (b *BaseLexer) getErrorDisplayForChar(c rune) string {
 	if c == TokenEOF {
 		return "<EOF>"
@@ -30,6 +30,27 @@ type ILexerATNSimulator interface {
 	Consume(input CharStream)
 }
 
+// positionTracker is implemented by lexer ATN simulators whose line and
+// column tracking can be configured, which BaseLexer.SetPositionTracking
+// needs. LexerATNSimulator implements it.
+type positionTracker interface {
+	GetPositionTracking() *PositionTracking
+	SetPositionTracking(tracking *PositionTracking)
+}
+
+// positionSetter is implemented by lexer ATN simulators that can move their
+// position back, which the lexer needs to match a token again once an
+// AppendableInputStream has received more input. LexerATNSimulator
+// implements it.
+type positionSetter interface {
+	SetPosition(line, column int)
+}
+
+var (
+	_ positionSetter  = &LexerATNSimulator{}
+	_ positionTracker = &LexerATNSimulator{}
+)
+
 type LexerATNSimulator struct {
 	*BaseATNSimulator
 
@@ -42,6 +63,7 @@ type LexerATNSimulator struct {
 	mode               int
 	prevAccept         *SimState
 	MatchCalls         int
+	positionTracking   *PositionTracking
 }
 
 func NewLexerATNSimulator(recog Lexer, atn *ATN, decisionToDFA []*DFA, sharedContextCache *PredictionContextCache) *LexerATNSimulator {
@@ -87,6 +109,9 @@ func (l *LexerATNSimulator) Match(input CharStream, mode int) int {
 
 	l.startIndex = input.Index()
 	l.prevAccept.reset()
+	if in, ok := input.(incompleteCharStream); ok {
+		in.resetHitEnd()
+	}
 
 	dfa := l.decisionToDFA[mode]
 
@@ -244,6 +269,12 @@ func (l *LexerATNSimulator) computeTargetState(input CharStream, s *DFAState, t
 }
 
 func (l *LexerATNSimulator) failOrAccept(prevAccept *SimState, input CharStream, reach ATNConfigSet, t int) int {
+	// Neither accept nor fail if the input may still grow past the point
+	// where the match stopped; more text could extend or complete the token.
+	if in, ok := input.(incompleteCharStream); ok && in.needsMoreInput() {
+		return TokenNeedMoreInput
+	}
+
 	if l.prevAccept.dfaState != nil {
 		lexerActionExecutor := prevAccept.dfaState.lexerActionExecutor
 		l.accept(input, lexerActionExecutor, l.startIndex, prevAccept.index, prevAccept.line, prevAccept.column)
@@ -608,7 +639,9 @@ func (l *LexerATNSimulator) GetText(input CharStream) string {
 
 func (l *LexerATNSimulator) Consume(input CharStream) {
 	curChar := input.LA(1)
-	if curChar == int('\n') {
+	if l.positionTracking != nil {
+		l.Line, l.CharPositionInLine = l.positionTracking.advance(l.Line, l.CharPositionInLine, curChar, input.LA(-1))
+	} else if curChar == int('\n') {
 		l.Line++
 		l.CharPositionInLine = 0
 	} else {
@@ -617,6 +650,26 @@ func (l *LexerATNSimulator) Consume(input CharStream) {
 	input.Consume()
 }
 
+// SetPosition sets Line and CharPositionInLine, for example to move back to
+// the start of a token that is about to be Matched again.
+func (l *LexerATNSimulator) SetPosition(line, column int) {
+	l.Line = line
+	l.CharPositionInLine = column
+}
+
+// GetPositionTracking returns the rules used to advance Line and
+// CharPositionInLine, or nil if the defaults are in use.
+func (l *LexerATNSimulator) GetPositionTracking() *PositionTracking {
+	return l.positionTracking
+}
+
+// SetPositionTracking sets the rules used to advance Line and
+// CharPositionInLine. With nil, only '\n' ends a line and every other
+// character is one column wide.
+func (l *LexerATNSimulator) SetPositionTracking(tracking *PositionTracking) {
+	l.positionTracking = tracking
+}
+
 func (l *LexerATNSimulator) GetCharPositionInLine() int {
 	return l.CharPositionInLine
 }
@@ -0,0 +1,161 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"strconv"
+)
+
+// LexerErrorStrategy decides what a lexer does with input no lexer rule
+// Matches. BaseLexer.NextToken calls ReportError, which typically notifies
+// the error listeners with LexerErrorHost.NotifyListeners, and then Recover,
+// which must either consume input and return the type of the token to emit
+// for the characters from the start of the token up to the current input
+// position, return LexerSkip to drop those characters, or panic to abort
+// lexing.
+//
+// Recover must consume at least one character unless the input is at EOF;
+// otherwise the lexer fails on the same input again and again.
+//
+// Install a strategy with BaseLexer.SetErrorHandler:
+//
+//	lexer.SetErrorHandler(antlr.NewErrorTokenLexerErrorStrategy(MyLexerERROR))
+type LexerErrorStrategy interface {
+	ReportError(lexer LexerErrorHost, e RecognitionException)
+	Recover(lexer LexerErrorHost, e RecognitionException) int
+}
+
+// LexerErrorHost is the lexer a LexerErrorStrategy reports and recovers
+// for, giving it access to the state of the token that failed to Match.
+// BaseLexer implements it.
+type LexerErrorHost interface {
+	Lexer
+
+	GetInterpreter() ILexerATNSimulator
+	GetTokenStartLine() int
+	GetTokenStartColumn() int
+	GetErrorText() string
+	NotifyListeners(e RecognitionException)
+	Recover(re RecognitionException)
+}
+
+var _ LexerErrorHost = &BaseLexer{}
+
+// DefaultLexerErrorStrategy reports the error to the lexer's error listeners,
+// drops the offending character and goes on lexing after it. It is the
+// strategy of a new lexer.
+type DefaultLexerErrorStrategy struct {
+}
+
+var _ LexerErrorStrategy = &DefaultLexerErrorStrategy{}
+
+func NewDefaultLexerErrorStrategy() *DefaultLexerErrorStrategy {
+	return new(DefaultLexerErrorStrategy)
+}
+
+func (d *DefaultLexerErrorStrategy) ReportError(lexer LexerErrorHost, e RecognitionException) {
+	lexer.NotifyListeners(e)
+}
+
+func (d *DefaultLexerErrorStrategy) Recover(lexer LexerErrorHost, e RecognitionException) int {
+	lexer.Recover(e)
+	return LexerSkip
+}
+
+// ErrorTokenLexerErrorStrategy reports and recovers like
+// DefaultLexerErrorStrategy but emits the characters that could not be
+// Matched as a token of a dedicated type, so they remain visible to the
+// parser and in the token stream.
+type ErrorTokenLexerErrorStrategy struct {
+	*DefaultLexerErrorStrategy
+
+	tokenType int
+}
+
+var _ LexerErrorStrategy = &ErrorTokenLexerErrorStrategy{}
+
+// NewErrorTokenLexerErrorStrategy creates a strategy emitting tokens of type
+// tokenType, typically an ERROR token declared in the grammar's tokens {}
+// section.
+func NewErrorTokenLexerErrorStrategy(tokenType int) *ErrorTokenLexerErrorStrategy {
+	return &ErrorTokenLexerErrorStrategy{DefaultLexerErrorStrategy: NewDefaultLexerErrorStrategy(), tokenType: tokenType}
+}
+
+func (et *ErrorTokenLexerErrorStrategy) Recover(lexer LexerErrorHost, e RecognitionException) int {
+	lexer.Recover(e)
+	return et.tokenType
+}
+
+// ResyncLexerErrorStrategy reports the error like DefaultLexerErrorStrategy
+// and recovers by consuming characters up to, but not including, the next
+// character of a resynchronization set, such as a statement terminator or
+// whitespace, or up to EOF. The characters consumed are emitted as one token
+// or dropped.
+type ResyncLexerErrorStrategy struct {
+	*DefaultLexerErrorStrategy
+
+	isResyncChar func(c rune) bool
+	tokenType    int
+}
+
+var _ LexerErrorStrategy = &ResyncLexerErrorStrategy{}
+
+// NewResyncLexerErrorStrategy creates a strategy resynchronizing to the
+// characters for which isResyncChar returns true. tokenType is the type of
+// the token emitted for the consumed characters, or LexerSkip to drop them.
+func NewResyncLexerErrorStrategy(isResyncChar func(c rune) bool, tokenType int) *ResyncLexerErrorStrategy {
+	return &ResyncLexerErrorStrategy{DefaultLexerErrorStrategy: NewDefaultLexerErrorStrategy(), isResyncChar: isResyncChar, tokenType: tokenType}
+}
+
+func (r *ResyncLexerErrorStrategy) Recover(lexer LexerErrorHost, e RecognitionException) int {
+	lexer.Recover(e)
+	input := lexer.GetInputStream()
+	for c := input.LA(1); c != TokenEOF && !r.isResyncChar(rune(c)); c = input.LA(1) {
+		lexer.GetInterpreter().Consume(input)
+	}
+	return r.tokenType
+}
+
+// BailLexerErrorStrategy aborts lexing at the first error by panicking with a
+// *LexerError, which callers of NextToken, or of the parser reading from the
+// lexer, recover to find out where lexing failed. Use it when invalid input
+// is to be rejected as a whole.
+//
+// The error is not reported to the lexer's error listeners, as the
+// *LexerError already carries it.
+type BailLexerErrorStrategy struct {
+}
+
+var _ LexerErrorStrategy = &BailLexerErrorStrategy{}
+
+func NewBailLexerErrorStrategy() *BailLexerErrorStrategy {
+	return new(BailLexerErrorStrategy)
+}
+
+func (b *BailLexerErrorStrategy) ReportError(lexer LexerErrorHost, e RecognitionException) {
+}
+
+func (b *BailLexerErrorStrategy) Recover(lexer LexerErrorHost, e RecognitionException) int {
+	panic(&LexerError{
+		Line:      lexer.GetTokenStartLine(),
+		Column:    lexer.GetTokenStartColumn(),
+		Text:      lexer.GetErrorText(),
+		Exception: e,
+	})
+}
+
+// LexerError is the error BailLexerErrorStrategy aborts lexing with.
+type LexerError struct {
+	// Line and Column locate the start of the offending characters.
+	Line, Column int
+	// Text is the text from the start of the token being Matched up to and
+	// including the first character that could not be Matched.
+	Text      string
+	Exception RecognitionException
+}
+
+func (e *LexerError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ":" + strconv.Itoa(e.Column) + " token recognition error at: '" + e.Text + "'"
+}
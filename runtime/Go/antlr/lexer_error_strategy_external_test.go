@@ -0,0 +1,57 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+)
+
+// countingLexerStrategy is a LexerErrorStrategy written outside package
+// antlr. It reports nothing, records the text of each error and emits the
+// offending character as a token of type tokenType.
+type countingLexerStrategy struct {
+	tokenType int
+	errors    []string
+}
+
+func (c *countingLexerStrategy) ReportError(lexer antlr.LexerErrorHost, e antlr.RecognitionException) {
+	c.errors = append(c.errors, lexer.GetErrorText())
+}
+
+func (c *countingLexerStrategy) Recover(lexer antlr.LexerErrorHost, e antlr.RecognitionException) int {
+	lexer.GetInterpreter().Consume(lexer.GetInputStream())
+	return c.tokenType
+}
+
+func TestCustomLexerErrorStrategy(t *testing.T) {
+	const tokenERROR = 8
+
+	lexer := antlr.NewLexerB(antlr.NewInputStream("a?b #\n?"))
+	lexer.RemoveErrorListeners()
+	strategy := &countingLexerStrategy{tokenType: tokenERROR}
+	lexer.SetErrorHandler(strategy)
+
+	var types []int
+	var texts []string
+	var lines []int
+	for _, token := range lexer.GetAllTokens() {
+		types = append(types, token.GetTokenType())
+		texts = append(texts, token.GetText())
+		lines = append(lines, token.GetLine())
+	}
+
+	expectedTypes := []int{antlr.LexerBID, tokenERROR, antlr.LexerBID, antlr.LexerBWS, tokenERROR, tokenERROR, tokenERROR}
+	expectedTexts := []string{"a", "?", "b", " ", "#", "\n", "?"}
+	expectedLines := []int{1, 1, 1, 1, 1, 1, 2}
+	if !reflect.DeepEqual(types, expectedTypes) || !reflect.DeepEqual(texts, expectedTexts) || !reflect.DeepEqual(lines, expectedLines) {
+		t.Errorf("got tokens %v %q on lines %v, want %v %q on lines %v", types, texts, lines, expectedTypes, expectedTexts, expectedLines)
+	}
+	if !reflect.DeepEqual(strategy.errors, []string{"?", "#", "\n", "?"}) {
+		t.Errorf("got errors %q, want %q", strategy.errors, []string{"?", "#", "\n", "?"})
+	}
+}
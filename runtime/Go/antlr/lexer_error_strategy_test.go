@@ -0,0 +1,115 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+// lexerBERROR is an extra token type for the characters LexerB cannot Match.
+const lexerBERROR = 8
+
+func lexWithErrorStrategy(src string, strategy LexerErrorStrategy) []Token {
+	lexer := NewLexerB(NewInputStream(src))
+	lexer.RemoveErrorListeners()
+	lexer.SetErrorHandler(strategy)
+	return lexer.GetAllTokens()
+}
+
+func tokenTypesAndTexts(tokens []Token) ([]int, []string) {
+	var types []int
+	var texts []string
+	for _, token := range tokens {
+		types = append(types, token.GetTokenType())
+		texts = append(texts, token.GetText())
+	}
+	return types, texts
+}
+
+func TestDefaultLexerErrorStrategy(t *testing.T) {
+	assert := assertNew(t)
+
+	lexer := NewLexerB(NewInputStream(""))
+	assert.Equal(NewDefaultLexerErrorStrategy(), lexer.GetErrorHandler())
+
+	types, texts := tokenTypesAndTexts(lexWithErrorStrategy("a?b", NewDefaultLexerErrorStrategy()))
+	assert.Equal([]int{LexerBID, LexerBID}, types)
+	assert.Equal([]string{"a", "b"}, texts)
+}
+
+func TestLexerErrorStrategyReportError(t *testing.T) {
+	assert := assertNew(t)
+
+	for _, strategy := range []LexerErrorStrategy{
+		NewDefaultLexerErrorStrategy(),
+		NewErrorTokenLexerErrorStrategy(lexerBERROR),
+		NewResyncLexerErrorStrategy(func(c rune) bool { return c == ';' }, LexerSkip),
+	} {
+		lexer := NewLexerB(NewInputStream("a?b"))
+		errors := &syntaxErrorCounter{DefaultErrorListener: NewDefaultErrorListener()}
+		lexer.RemoveErrorListeners()
+		lexer.AddErrorListener(errors)
+		lexer.SetErrorHandler(strategy)
+		lexer.GetAllTokens()
+		assert.Equal([]string{"token recognition error at: '?'"}, errors.errors)
+	}
+}
+
+func TestErrorTokenLexerErrorStrategy(t *testing.T) {
+	assert := assertNew(t)
+
+	tokens := lexWithErrorStrategy("a?b", NewErrorTokenLexerErrorStrategy(lexerBERROR))
+	types, texts := tokenTypesAndTexts(tokens)
+	assert.Equal([]int{LexerBID, lexerBERROR, LexerBID}, types)
+	assert.Equal([]string{"a", "?", "b"}, texts)
+	assert.Equal(1, tokens[1].GetStart())
+	assert.Equal(1, tokens[1].GetStop())
+	assert.Equal(1, tokens[1].GetColumn())
+}
+
+func TestResyncLexerErrorStrategy(t *testing.T) {
+	assert := assertNew(t)
+	isSemi := func(c rune) bool { return c == ';' }
+
+	types, texts := tokenTypesAndTexts(lexWithErrorStrategy("a?#b;c", NewResyncLexerErrorStrategy(isSemi, lexerBERROR)))
+	assert.Equal([]int{LexerBID, lexerBERROR, LexerBSEMI, LexerBID}, types)
+	assert.Equal([]string{"a", "?#b", ";", "c"}, texts)
+
+	types, texts = tokenTypesAndTexts(lexWithErrorStrategy("a?#b;c", NewResyncLexerErrorStrategy(isSemi, LexerSkip)))
+	assert.Equal([]int{LexerBID, LexerBSEMI, LexerBID}, types)
+	assert.Equal([]string{"a", ";", "c"}, texts)
+
+	// without a resync character the rest of the input is consumed
+	types, texts = tokenTypesAndTexts(lexWithErrorStrategy("a?b", NewResyncLexerErrorStrategy(isSemi, lexerBERROR)))
+	assert.Equal([]int{LexerBID, lexerBERROR}, types)
+	assert.Equal([]string{"a", "?b"}, texts)
+}
+
+func TestBailLexerErrorStrategy(t *testing.T) {
+	assert := assertNew(t)
+
+	lexer := NewLexerB(NewInputStream("a=?b"))
+	errors := &syntaxErrorCounter{DefaultErrorListener: NewDefaultErrorListener()}
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errors)
+	lexer.SetErrorHandler(NewBailLexerErrorStrategy())
+	assert.Equal(LexerBID, lexer.NextToken().GetTokenType())
+	assert.Equal(LexerBASSIGN, lexer.NextToken().GetTokenType())
+
+	var err *LexerError
+	func() {
+		defer func() {
+			err, _ = recover().(*LexerError)
+		}()
+		lexer.NextToken()
+	}()
+
+	assert.NotNil(err)
+	assert.Equal(1, err.Line)
+	assert.Equal(2, err.Column)
+	assert.Equal("?", err.Text)
+	assert.Equal("line 1:2 token recognition error at: '?'", err.Error())
+	assert.Equal(0, len(errors.errors))
+}
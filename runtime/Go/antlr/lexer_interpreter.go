@@ -0,0 +1,63 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+// LexerInterpreter is a Lexer that runs a lexer ATN directly, without code
+// generated for the grammar. Tools use it to tokenize input with a grammar
+// loaded at runtime, for example one deserialized from the tool's output
+// with ATNDeserializer.DeserializeFromUInt16.
+//
+// Lexer commands such as skip, channel and pushMode work as in a generated
+// lexer. Embedded actions are ignored and semantic predicates evaluate to
+// true, as their code is only available in the target language.
+type LexerInterpreter struct {
+	*BaseLexer
+
+	channelNames []string
+	modeNames    []string
+}
+
+// NewLexerInterpreter creates a lexer for atn, which must be a lexer ATN.
+// vocabulary names the token types, ruleNames the lexer rules in ATN order
+// and channelNames and modeNames the channels and modes, including the
+// predefined DEFAULT_TOKEN_CHANNEL, HIDDEN and DEFAULT_MODE.
+func NewLexerInterpreter(grammarFileName string, vocabulary Vocabulary, ruleNames, channelNames, modeNames []string, atn *ATN, input CharStream) *LexerInterpreter {
+	if atn.grammarType != ATNTypeLexer {
+		panic("The ATN must be a lexer ATN.")
+	}
+
+	l := new(LexerInterpreter)
+
+	l.BaseLexer = NewBaseLexer(input)
+	l.Virt = l
+
+	decisionToDFA := make([]*DFA, len(atn.DecisionToState))
+	for i, ds := range atn.DecisionToState {
+		decisionToDFA[i] = NewDFA(ds, i)
+	}
+	l.Interpreter = NewLexerATNSimulator(l, atn, decisionToDFA, NewPredictionContextCache())
+
+	l.GrammarFileName = grammarFileName
+	l.RuleNames = ruleNames
+	l.channelNames = channelNames
+	l.modeNames = modeNames
+	l.setVocabulary(vocabulary)
+
+	return l
+}
+
+// GetChannelNames returns the names of the lexer's channels.
+func (l *LexerInterpreter) GetChannelNames() []string {
+	return l.channelNames
+}
+
+// GetModeNames returns the names of the lexer's modes.
+func (l *LexerInterpreter) GetModeNames() []string {
+	return l.modeNames
+}
+
+// Action ignores embedded actions, which cannot be interpreted.
+func (l *LexerInterpreter) Action(localctx RuleContext, ruleIndex, actionIndex int) {
+}
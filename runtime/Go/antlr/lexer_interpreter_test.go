@@ -0,0 +1,45 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+func newLexerBInterpreter(input CharStream) *LexerInterpreter {
+	vocabulary := NewVocabulary(lexerB_lexerLiteralNames, lexerB_lexerSymbolicNames)
+	return NewLexerInterpreter("LexerB.g4", vocabulary, lexerB_lexerRuleNames,
+		lexerB_lexerChannelNames, lexerB_lexerModeNames, lexerB_lexerAtn, input)
+}
+
+func TestLexerInterpreter(t *testing.T) {
+	assert := assertNew(t)
+	src := "abc = 123; x = y + 4 * z"
+
+	actual := newLexerBInterpreter(NewInputStream(src)).GetAllTokens()
+	expected := NewLexerB(NewInputStream(src)).GetAllTokens()
+
+	assert.Equal(len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(expected[i].(*CommonToken).String(), actual[i].(*CommonToken).String())
+	}
+}
+
+func TestLexerInterpreterNames(t *testing.T) {
+	assert := assertNew(t)
+	lexer := newLexerBInterpreter(NewInputStream(""))
+
+	assert.Equal("LexerB.g4", lexer.GetSourceName())
+	assert.Equal(LexerBASSIGN, lexer.GetTokenType("ASSIGN"))
+	assert.Equal("'='", lexer.GetVocabulary().GetDisplayName(LexerBASSIGN))
+	assert.Equal(3, lexer.GetRuleIndexMap()["ASSIGN"])
+	assert.Equal("HIDDEN", lexer.GetChannelNames()[LexerHidden])
+	assert.Equal("DEFAULT_MODE", lexer.GetModeNames()[LexerDefaultMode])
+	assert.Equal(TokenEOF, lexer.NextToken().GetTokenType())
+
+	assert.Panics(func() {
+		NewLexerInterpreter("P.g4", VocabularyEMPTY, nil, nil, nil, NewATN(ATNTypeParser, 0), nil)
+	})
+}
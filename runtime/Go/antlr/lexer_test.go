@@ -0,0 +1,141 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+// digitSplittingLexer emits one INT token per digit of each integer.
+type digitSplittingLexer struct {
+	*LexerB
+}
+
+func newDigitSplittingLexer(src string) *digitSplittingLexer {
+	l := &digitSplittingLexer{NewLexerB(NewInputStream(src))}
+	l.Virt = l
+	return l
+}
+
+func (l *digitSplittingLexer) Emit() Token {
+	if l.thetype != LexerBINT {
+		return l.BaseLexer.Emit()
+	}
+
+	start := l.TokenStartCharIndex
+	var digits []Token
+	for i := start; i < l.GetCharIndex(); i++ {
+		column := l.TokenStartColumn + i - start
+		digits = append(digits, l.factory.Create(l.tokenFactorySourcePair, LexerBINT, "", l.channel, i, i,
+			l.TokenStartLine, column, l.TokenStartLine, column+1))
+	}
+	l.EmitToken(digits[0])
+	l.EmitTokens(digits[1:]...)
+	return digits[0]
+}
+
+// wsActionSimulator runs an action after each WS token Matched, as a lexer
+// action in the grammar would.
+type wsActionSimulator struct {
+	*LexerATNSimulator
+	action func()
+}
+
+func (s *wsActionSimulator) Match(input CharStream, mode int) int {
+	ttype := s.LexerATNSimulator.Match(input, mode)
+	if ttype == LexerBWS {
+		s.action()
+	}
+	return ttype
+}
+
+func TestLexerEmitTokens(t *testing.T) {
+	assert := assertNew(t)
+	lexer := newDigitSplittingLexer("x=123;y=45")
+
+	var types []int
+	var texts []string
+	for token := lexer.NextToken(); token.GetTokenType() != TokenEOF; token = lexer.NextToken() {
+		types = append(types, token.GetTokenType())
+		texts = append(texts, token.GetText())
+	}
+
+	assert.Equal([]int{LexerBID, LexerBASSIGN, LexerBINT, LexerBINT, LexerBINT, LexerBSEMI,
+		LexerBID, LexerBASSIGN, LexerBINT, LexerBINT}, types)
+	assert.Equal([]string{"x", "=", "1", "2", "3", ";", "y", "=", "4", "5"}, texts)
+}
+
+func TestLexerEmitTokensAfterEmit(t *testing.T) {
+	assert := assertNew(t)
+	lexer := NewLexerB(NewInputStream("a b"))
+	extra1 := newTestCommonToken(LexerBID, "x", TokenDefaultChannel)
+	extra2 := newTestCommonToken(LexerBID, "y", TokenDefaultChannel)
+
+	lexer.EmitTokens()
+	assert.Equal(0, len(lexer.pendingTokens))
+
+	// queued tokens come before any more input is Matched
+	assert.Equal("a", lexer.NextToken().GetText())
+	lexer.EmitTokens(extra1, extra2)
+	assert.Equal(extra1, lexer.NextToken())
+	assert.Equal(extra2, lexer.NextToken())
+	assert.Equal(" ", lexer.NextToken().GetText())
+
+	// reset drops pending tokens
+	lexer.EmitTokens(extra1)
+	lexer.reset()
+	assert.Equal("a", lexer.NextToken().GetText())
+}
+
+func TestLexerEmitTokensWithSkip(t *testing.T) {
+	assert := assertNew(t)
+	lexer := NewLexerB(NewInputStream("a  b"))
+	x := newTestCommonToken(LexerBID, "x", TokenDefaultChannel)
+	y := newTestCommonToken(LexerBID, "y", TokenDefaultChannel)
+
+	// WS : ' '+ { l.EmitTokens(x, y) } -> skip ;
+	lexer.Interpreter = &wsActionSimulator{lexer.Interpreter.(*LexerATNSimulator), func() {
+		lexer.EmitTokens(x, y)
+		lexer.Skip()
+	}}
+
+	types, texts := tokenTypesAndTexts(lexer.GetAllTokens())
+	assert.Equal([]int{LexerBID, LexerBID, LexerBID, LexerBID}, types)
+	assert.Equal([]string{"a", "x", "y", "b"}, texts)
+	assert.Equal(TokenEOF, lexer.NextToken().GetTokenType())
+}
+
+func TestLexerEmitTokensAfterEmitInAction(t *testing.T) {
+	assert := assertNew(t)
+	lexer := NewLexerB(NewInputStream("a b"))
+	x := newTestCommonToken(LexerBID, "x", TokenDefaultChannel)
+
+	// WS : ' '+ { l.Emit(); l.EmitTokens(x) } ;
+	lexer.Interpreter = &wsActionSimulator{lexer.Interpreter.(*LexerATNSimulator), func() {
+		lexer.SetType(LexerBWS)
+		lexer.Emit()
+		lexer.EmitTokens(x)
+	}}
+
+	_, texts := tokenTypesAndTexts(lexer.GetAllTokens())
+	assert.Equal([]string{"a", " ", "x", "b"}, texts)
+}
+
+func TestLexerEmitTokensWithMore(t *testing.T) {
+	assert := assertNew(t)
+	lexer := NewLexerB(NewInputStream("a  b;"))
+	x := newTestCommonToken(LexerBID, "x", TokenDefaultChannel)
+
+	// WS : ' '+ { l.EmitTokens(x) } -> more ;
+	lexer.Interpreter = &wsActionSimulator{lexer.Interpreter.(*LexerATNSimulator), func() {
+		lexer.EmitTokens(x)
+		lexer.More()
+	}}
+
+	// the token completed after the More comes first
+	types, texts := tokenTypesAndTexts(lexer.GetAllTokens())
+	assert.Equal([]int{LexerBID, LexerBID, LexerBID, LexerBSEMI}, types)
+	assert.Equal([]string{"a", "  b", "x", ";"}, texts)
+}
@@ -0,0 +1,300 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	lineHasMultiByte      = 1 << iota // a character needs more than one UTF-8 byte
+	lineHasSurrogatePairs             // a character needs two UTF-16 code units
+	lineHasTabs                       // a tab is wider than one column
+)
+
+// LineIndex maps character indexes of a CharStream, as returned by
+// Token.GetStart and Token.GetStop, to lines and to columns counted in
+// characters, UTF-16 code units or UTF-8 bytes. Language servers use the
+// UTF-16 form, as required by the Language Server Protocol, and Go tooling the
+// UTF-8 form.
+//
+// Lines are 1-based and columns 0-based, as for tokens. By default only '\n'
+// ends a line and every character is one column wide, as for a lexer without
+// PositionTracking; NewLineIndexWithTracking follows the line terminators and
+// tab stops of a lexer configured with BaseLexer.SetPositionTracking. The
+// characters of a line terminator belong to the line they end.
+//
+// Looking up a line takes O(log n). Columns on lines that are pure ASCII, or
+// free of supplementary characters for UTF-16, need no further work; other
+// lines are scanned up to the column. UTF-16 and UTF-8 columns count a tab as
+// one unit whatever the tab width.
+type LineIndex struct {
+	data     []rune
+	tracking *PositionTracking
+
+	// lineStarts[i] is the index of the first character of line i+1.
+	lineStarts []int
+
+	// lineEnds[i] is the index of the terminator of line i+1. The last line
+	// has no terminator and no entry.
+	lineEnds []int
+
+	// lineFlags[i] records which encodings need more than one unit for some
+	// character of line i+1.
+	lineFlags []uint8
+}
+
+// LineLookupCharStream is a CharStream that translates between character
+// indexes and lines and columns, building a LineIndex of its input on first
+// use. InputStream, UTF8InputStream and ByteStream implement it.
+type LineLookupCharStream interface {
+	CharStream
+
+	// GetLineIndex returns the line table of the stream.
+	GetLineIndex() *LineIndex
+
+	// PositionOf returns the line (1..n) and column (0..n-1) of the
+	// character at index, for example the start of a token or of a
+	// RecognitionException's offending token.
+	PositionOf(index int) (line, column int)
+
+	// OffsetOf returns the index of the character at line and column, or -1
+	// if the line does not exist.
+	OffsetOf(line, column int) int
+
+	// LineText returns the text of line n without its line terminator, for
+	// showing source snippets in diagnostics.
+	LineText(n int) string
+}
+
+var (
+	_ LineLookupCharStream = &InputStream{}
+	_ LineLookupCharStream = &UTF8InputStream{}
+	_ LineLookupCharStream = &ByteStream{}
+)
+
+// runeSource is implemented by streams that hold their input as runes, so a
+// LineIndex can share the data instead of copying it.
+type runeSource interface {
+	runes() []rune
+}
+
+// NewLineIndex builds the line index of input by reading all of its text.
+func NewLineIndex(input CharStream) *LineIndex {
+	return NewLineIndexWithTracking(input, nil)
+}
+
+// NewLineIndexWithTracking builds the line index of input with the line
+// terminators and tab width of tracking, so that its lines and columns agree
+// with those of the tokens of a lexer using the same tracking. A nil tracking
+// is the default of NewLineIndex.
+func NewLineIndexWithTracking(input CharStream, tracking *PositionTracking) *LineIndex {
+	if rs, ok := input.(runeSource); ok {
+		return newLineIndex(rs.runes(), tracking)
+	}
+
+	if input.Size() == 0 {
+		return newLineIndex(nil, tracking)
+	}
+	return newLineIndex([]rune(input.GetText(0, input.Size()-1)), tracking)
+}
+
+func newLineIndex(data []rune, tracking *PositionTracking) *LineIndex {
+	if tracking == nil {
+		tracking = &PositionTracking{}
+	}
+	li := &LineIndex{
+		data:       data,
+		tracking:   tracking,
+		lineStarts: []int{0},
+		lineFlags:  []uint8{0},
+	}
+
+	lineEnd := -1
+	for i, c := range data {
+		last := len(li.lineFlags) - 1
+		if c >= utf8.RuneSelf {
+			li.lineFlags[last] |= lineHasMultiByte
+		}
+		if c > 0xFFFF {
+			li.lineFlags[last] |= lineHasSurrogatePairs
+		}
+		if c == '\t' && tracking.TabWidth > 1 {
+			li.lineFlags[last] |= lineHasTabs
+		}
+		if !tracking.isLineTerminator(int(c)) {
+			continue
+		}
+		if lineEnd < 0 {
+			lineEnd = i
+		}
+		if c == '\r' && tracking.CRLFAsOneBreak && tracking.isLineTerminator('\n') && i+1 < len(data) && data[i+1] == '\n' {
+			// the '\n' ends the line
+			continue
+		}
+		li.lineEnds = append(li.lineEnds, lineEnd)
+		li.lineStarts = append(li.lineStarts, i+1)
+		li.lineFlags = append(li.lineFlags, 0)
+		lineEnd = -1
+	}
+
+	return li
+}
+
+// LineCount returns the number of lines. Text ending with a line terminator
+// has an empty last line.
+func (li *LineIndex) LineCount() int {
+	return len(li.lineStarts)
+}
+
+// LineOf returns the line holding the character at index. Indexes past the
+// end of the input are on the last line.
+func (li *LineIndex) LineOf(index int) int {
+	if index < 0 {
+		return 1
+	}
+
+	return sort.SearchInts(li.lineStarts, index+1)
+}
+
+// PositionOf returns the line of the character at index and its column
+// counted in characters, with tabs expanded to the next tab stop.
+func (li *LineIndex) PositionOf(index int) (line, column int) {
+	line, start := li.lineAndStart(index)
+	column = index - start
+	if li.lineFlags[line-1]&lineHasTabs != 0 {
+		column = 0
+		for _, c := range li.data[start:intMin(index, len(li.data))] {
+			column = li.nextColumn(column, c)
+		}
+		column += intMax(0, index-len(li.data))
+	}
+
+	return line, column
+}
+
+// OffsetOf returns the index of the character at line and column, the inverse
+// of PositionOf. A column within a tab yields the index of the tab, and a
+// column past the end of the line the index of the line's terminator. It
+// returns -1 if line does not exist or column is negative.
+func (li *LineIndex) OffsetOf(line, column int) int {
+	if line < 1 || line > len(li.lineStarts) || column < 0 {
+		return -1
+	}
+
+	start := li.lineStarts[line-1]
+	length := li.lineLength(line)
+	if li.lineFlags[line-1]&lineHasTabs == 0 {
+		return start + intMin(column, length)
+	}
+
+	c := 0
+	for i := start; i < start+length; i++ {
+		next := li.nextColumn(c, li.data[i])
+		if column < next {
+			return i
+		}
+		c = next
+	}
+
+	return start + length
+}
+
+// LineText returns the text of line n without its line terminator, or "" if
+// the line does not exist. A '\r' before a '\n' is removed too.
+func (li *LineIndex) LineText(n int) string {
+	start, stop := li.lineSpan(n)
+	return string(li.data[start:stop])
+}
+
+// UTF16Position returns the line of the character at index and its column
+// counted in UTF-16 code units.
+func (li *LineIndex) UTF16Position(index int) (line, column int) {
+	line, start := li.lineAndStart(index)
+	column = index - start
+	if li.lineFlags[line-1]&lineHasSurrogatePairs != 0 {
+		for _, c := range li.data[start:intMin(index, len(li.data))] {
+			if c > 0xFFFF {
+				column++
+			}
+		}
+	}
+
+	return line, column
+}
+
+// UTF8Position returns the line of the character at index and its column
+// counted in UTF-8 bytes.
+func (li *LineIndex) UTF8Position(index int) (line, column int) {
+	line, start := li.lineAndStart(index)
+	column = index - start
+	if li.lineFlags[line-1]&lineHasMultiByte != 0 {
+		column = 0
+		for _, c := range li.data[start:intMin(index, len(li.data))] {
+			column += utf8.RuneLen(c)
+		}
+		column += intMax(0, index-len(li.data))
+	}
+
+	return line, column
+}
+
+// UTF16TokenRange returns the start of t and the position just past its last
+// character, with columns counted in UTF-16 code units.
+func (li *LineIndex) UTF16TokenRange(t Token) (startLine, startColumn, endLine, endColumn int) {
+	startLine, startColumn = li.UTF16Position(t.GetStart())
+	endLine, endColumn = li.UTF16Position(t.GetStop() + 1)
+	return
+}
+
+// UTF8TokenRange returns the start of t and the position just past its last
+// character, with columns counted in UTF-8 bytes.
+func (li *LineIndex) UTF8TokenRange(t Token) (startLine, startColumn, endLine, endColumn int) {
+	startLine, startColumn = li.UTF8Position(t.GetStart())
+	endLine, endColumn = li.UTF8Position(t.GetStop() + 1)
+	return
+}
+
+// lineLength returns the number of characters on line n, excluding the
+// terminator that ends it.
+func (li *LineIndex) lineLength(n int) int {
+	if n < len(li.lineStarts) {
+		return li.lineEnds[n-1] - li.lineStarts[n-1]
+	}
+
+	return len(li.data) - li.lineStarts[n-1]
+}
+
+// lineSpan returns the index of the first character of line n and the index
+// just past its last character, before a '\r' preceding a '\n' terminator.
+// An empty span is returned if the line does not exist.
+func (li *LineIndex) lineSpan(n int) (start, stop int) {
+	if n < 1 || n > len(li.lineStarts) {
+		return 0, 0
+	}
+
+	start = li.lineStarts[n-1]
+	stop = start + li.lineLength(n)
+	if n < len(li.lineStarts) && li.data[stop] == '\n' && stop > start && li.data[stop-1] == '\r' {
+		stop--
+	}
+
+	return start, stop
+}
+
+// nextColumn returns the column following the character c at column.
+func (li *LineIndex) nextColumn(column int, c rune) int {
+	_, column = li.tracking.advance(1, column, int(c), TokenEOF)
+	return column
+}
+
+// lineAndStart returns the line holding index and the index of its first
+// character.
+func (li *LineIndex) lineAndStart(index int) (int, int) {
+	index = intMax(0, index)
+	line := li.LineOf(index)
+	return line, li.lineStarts[line-1]
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineIndexColumns(t *testing.T) {
+	assert := assertNew(t)
+	li := NewLineIndex(NewInputStream("ab\né😀x\n"))
+
+	assert.Equal(3, li.LineCount())
+	assert.Equal(1, li.LineOf(2))
+	assert.Equal(2, li.LineOf(3))
+	assert.Equal(3, li.LineOf(7))
+
+	type position struct{ line, column int }
+	utf16 := func(i int) position { l, c := li.UTF16Position(i); return position{l, c} }
+	utf8 := func(i int) position { l, c := li.UTF8Position(i); return position{l, c} }
+
+	assert.Equal(position{1, 1}, utf16(1))
+	assert.Equal(position{1, 1}, utf8(1))
+
+	// é is one UTF-16 unit and two UTF-8 bytes, 😀 two units and four bytes
+	assert.Equal(position{2, 1}, utf16(4))
+	assert.Equal(position{2, 2}, utf8(4))
+	assert.Equal(position{2, 3}, utf16(5))
+	assert.Equal(position{2, 6}, utf8(5))
+	assert.Equal(position{3, 0}, utf16(7))
+}
+
+func TestLineIndexTokenRange(t *testing.T) {
+	assert := assertNew(t)
+	input := NewUTF8InputStream([]byte(strings.Repeat(" ", 3) + "abc = 42;"))
+	tokens := NewLexerB(input).GetAllTokens()
+	li := NewLineIndex(input)
+
+	startLine, startColumn, endLine, endColumn := li.UTF16TokenRange(tokens[1])
+	assert.Equal("abc", tokens[1].GetText())
+	assert.Equal([]int{1, 3, 1, 6}, []int{startLine, startColumn, endLine, endColumn})
+}
+
+func TestInputStreamLineLookup(t *testing.T) {
+	assert := assertNew(t)
+	input := NewInputStream("first\r\nsecond\n\nlast")
+
+	line, column := input.PositionOf(9)
+	assert.Equal([]int{2, 2}, []int{line, column})
+	assert.Equal(9, input.OffsetOf(2, 2))
+	assert.Equal(13, input.OffsetOf(2, 99))
+	assert.Equal(-1, input.OffsetOf(5, 0))
+
+	assert.Equal("first", input.LineText(1))
+	assert.Equal("second", input.LineText(2))
+	assert.Equal("", input.LineText(3))
+	assert.Equal("last", input.LineText(4))
+	assert.Equal("", input.LineText(5))
+}
+
+func TestLineIndexWithTracking(t *testing.T) {
+	assert := assertNew(t)
+	src := "a\tb\rc\r\nd\u2028e\tf\n\tg"
+	tracking := NewPositionTracking(4)
+	input := NewInputStream(src)
+	lexer := NewLexerB(input)
+	lexer.SetPositionTracking(tracking)
+	positions := consumePositions(lexer, input)
+	li := NewLineIndexWithTracking(input, tracking)
+
+	// every character that is not part of a line terminator is where the
+	// lexer puts it
+	for i, c := range []rune(src) {
+		if tracking.isLineTerminator(int(c)) {
+			continue
+		}
+		line, column := li.PositionOf(i)
+		assert.Equal(positions[i], [2]int{line, column})
+		assert.Equal(i, li.OffsetOf(line, column))
+	}
+	assert.Equal(5, li.LineCount())
+	assert.Equal([]string{"a\tb", "c", "d", "e\tf", "\tg"},
+		[]string{li.LineText(1), li.LineText(2), li.LineText(3), li.LineText(4), li.LineText(5)})
+
+	// a column within a tab is the tab, one past the line its terminator
+	assert.Equal(1, li.OffsetOf(1, 2))
+	assert.Equal(3, li.OffsetOf(1, 99))
+	// UTF-16 columns count the tab as one unit
+	line, column := li.UTF16Position(2)
+	assert.Equal([2]int{1, 2}, [2]int{line, column})
+
+	// without tracking only '\n' ends a line and a tab is one column
+	li = NewLineIndex(input)
+	assert.Equal(3, li.LineCount())
+	line, column = li.PositionOf(2)
+	assert.Equal([2]int{1, 2}, [2]int{line, column})
+}
+
+func TestLineLookupCharStreams(t *testing.T) {
+	assert := assertNew(t)
+	src := "ab\r\nçd\n\xffe"
+
+	for _, input := range []LineLookupCharStream{
+		NewInputStream(src),
+		NewUTF8InputStream([]byte(src)),
+	} {
+		// 'd' is the second character of line 2
+		line, column := input.PositionOf(5)
+		assert.Equal([2]int{2, 1}, [2]int{line, column})
+		assert.Equal(5, input.OffsetOf(2, 1))
+		assert.Equal("ab", input.LineText(1))
+		assert.Equal("çd", input.LineText(2))
+		assert.Equal(3, input.GetLineIndex().LineCount())
+	}
+	// the invalid byte is kept by UTF8InputStream
+	assert.Equal("\xffe", NewUTF8InputStream([]byte(src)).LineText(3))
+
+	// ByteStream counts bytes, so ç takes two columns
+	input := NewByteStream([]byte(src))
+	line, column := input.PositionOf(6)
+	assert.Equal([2]int{2, 2}, [2]int{line, column})
+	assert.Equal(6, input.OffsetOf(2, 2))
+	assert.Equal("çd", input.LineText(2))
+	assert.Equal("\xffe", input.LineText(3))
+	assert.Equal("", input.LineText(4))
+}
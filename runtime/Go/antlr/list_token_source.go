@@ -0,0 +1,159 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+// ListTokenSource is a TokenSource that returns the tokens of a slice, for
+// example tokens produced by a hand-written scanner, a cached lexer run or a
+// test. If the slice does not end with an EOF token, one is created with the
+// token factory once the slice is exhausted, positioned just after the last
+// token.
+type ListTokenSource struct {
+	tokens []Token
+
+	// sourceName overrides the name returned by GetSourceName. When empty,
+	// the name of the current input stream is used.
+	sourceName string
+
+	// i is the index into tokens of the token to return from the next call
+	// to NextToken. The end of the input is reached when i is greater than
+	// or equal to the number of items in tokens.
+	i int
+
+	// eofToken is the EOF token, created by NextToken or taken from the end
+	// of tokens, once it has been returned.
+	eofToken Token
+
+	factory TokenFactory
+}
+
+// NewListTokenSource creates a token source returning tokens. The name of the
+// source is taken from the tokens' input stream.
+func NewListTokenSource(tokens []Token) *ListTokenSource {
+	return NewListTokenSourceWithName(tokens, "")
+}
+
+// NewListTokenSourceWithName creates a token source returning tokens and
+// reporting sourceName from GetSourceName.
+func NewListTokenSourceWithName(tokens []Token, sourceName string) *ListTokenSource {
+	return &ListTokenSource{
+		tokens:     tokens,
+		sourceName: sourceName,
+		factory:    CommonTokenFactoryDEFAULT,
+	}
+}
+
+func (l *ListTokenSource) NextToken() Token {
+	if l.i >= len(l.tokens) {
+		if l.eofToken == nil {
+			start := -1
+			if len(l.tokens) > 0 {
+				previousStop := l.tokens[len(l.tokens)-1].GetStop()
+				if previousStop != -1 {
+					start = previousStop + 1
+				}
+			}
+
+			stop := intMax(-1, start-1)
+			line, column := l.GetLine(), l.GetCharPositionInLine()
+			l.eofToken = l.factory.Create(&TokenSourceCharStreamPair{l, l.GetInputStream()}, TokenEOF, "EOF", TokenDefaultChannel, start, stop, line, column, line, column)
+		}
+
+		return l.eofToken
+	}
+
+	t := l.tokens[l.i]
+	if l.i == len(l.tokens)-1 && t.GetTokenType() == TokenEOF {
+		l.eofToken = t
+	}
+
+	l.i++
+	return t
+}
+
+// Skip does nothing; a list token source has no current token to discard.
+func (l *ListTokenSource) Skip() {}
+
+// More does nothing; a list token source has no current token to extend.
+func (l *ListTokenSource) More() {}
+
+// GetLine returns the line of the next token. At the end of the list it
+// returns the line just after the last token.
+func (l *ListTokenSource) GetLine() int {
+	if l.i < len(l.tokens) {
+		return l.tokens[l.i].GetLine()
+	} else if l.eofToken != nil {
+		return l.eofToken.GetLine()
+	} else if len(l.tokens) > 0 {
+		// the end of input is where the last token ends
+		return l.tokens[len(l.tokens)-1].GetEndLine()
+	}
+
+	// only reach this if tokens is empty, meaning EOF occurs at the first
+	// position in the input
+	return 1
+}
+
+// GetCharPositionInLine returns the column of the next token. At the end of
+// the list it returns the column just after the last token.
+func (l *ListTokenSource) GetCharPositionInLine() int {
+	if l.i < len(l.tokens) {
+		return l.tokens[l.i].GetColumn()
+	} else if l.eofToken != nil {
+		return l.eofToken.GetColumn()
+	} else if len(l.tokens) > 0 {
+		// the end of input is where the last token ends
+		return l.tokens[len(l.tokens)-1].GetEndColumn()
+	}
+
+	// only reach this if tokens is empty, meaning EOF occurs at the first
+	// position in the input
+	return 0
+}
+
+// GetInputStream returns the input stream of the next token, or of the last
+// token at the end of the list. It returns nil if the tokens have no input
+// stream.
+func (l *ListTokenSource) GetInputStream() CharStream {
+	if l.i < len(l.tokens) {
+		return tokenInputStream(l.tokens[l.i])
+	} else if l.eofToken != nil {
+		return tokenInputStream(l.eofToken)
+	} else if len(l.tokens) > 0 {
+		return tokenInputStream(l.tokens[len(l.tokens)-1])
+	}
+
+	// no input stream information is available
+	return nil
+}
+
+func (l *ListTokenSource) GetSourceName() string {
+	if l.sourceName != "" {
+		return l.sourceName
+	}
+
+	if input := l.GetInputStream(); input != nil {
+		return input.GetSourceName()
+	}
+
+	return "List"
+}
+
+func (l *ListTokenSource) SetTokenFactory(factory TokenFactory) {
+	l.factory = factory
+}
+
+func (l *ListTokenSource) GetTokenFactory() TokenFactory {
+	return l.factory
+}
+
+// tokenInputStream returns the input stream of t, or nil for tokens created
+// without a source.
+func tokenInputStream(t Token) CharStream {
+	if t.GetSource() == nil {
+		return nil
+	}
+
+	return t.GetInputStream()
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+func TestListTokenSourceSynthesizesEOF(t *testing.T) {
+	assert := assertNew(t)
+	input := NewInputStream("ab cd = 1")
+	tokens := NewLexerB(input).GetAllTokens()
+
+	source := NewListTokenSource(tokens)
+	stream := NewCommonTokenStream(source, TokenDefaultChannel)
+	stream.Fill()
+
+	assert.Equal(len(tokens)+1, stream.Size())
+	eof := stream.Get(stream.Size() - 1)
+	assert.Equal(TokenEOF, eof.GetTokenType())
+	assert.Equal(9, eof.GetStart())
+	assert.Equal(8, eof.GetStop())
+	assert.Equal(1, eof.GetLine())
+	assert.Equal(9, eof.GetColumn())
+	assert.Equal(input.GetSourceName(), source.GetSourceName())
+
+	// EOF is returned for every call past the end of the list
+	assert.Equal(eof, source.NextToken())
+}
+
+func TestListTokenSourceEmpty(t *testing.T) {
+	assert := assertNew(t)
+	source := NewListTokenSourceWithName(nil, "empty")
+
+	eof := source.NextToken()
+	assert.Equal(TokenEOF, eof.GetTokenType())
+	assert.Equal(1, eof.GetLine())
+	assert.Equal(0, eof.GetColumn())
+	assert.Equal("empty", source.GetSourceName())
+}
+
+func TestListTokenSourceKeepsEOF(t *testing.T) {
+	assert := assertNew(t)
+	eof := newTestCommonToken(TokenEOF, "", LexerDefaultTokenChannel)
+	source := NewListTokenSource([]Token{
+		newTestCommonToken(1, "x", LexerDefaultTokenChannel),
+		eof,
+	})
+
+	assert.Equal("x", source.NextToken().GetText())
+	assert.Equal(eof, source.NextToken())
+	assert.Equal(eof, source.NextToken())
+	assert.Equal("List", source.GetSourceName())
+}
+
+func TestListTokenSourceEOFAfterTokenEnd(t *testing.T) {
+	assert := assertNew(t)
+	input := NewInputStream("a\rbc")
+
+	// a token spanning a line break that only the position tracking of its
+	// lexer knows about
+	token := CommonTokenFactoryDEFAULT.Create(&TokenSourceCharStreamPair{nil, input}, LexerBID, "", TokenDefaultChannel, 0, 3, 1, 0, 2, 2)
+	source := NewListTokenSource([]Token{token})
+	source.NextToken()
+
+	assert.Equal(2, source.GetLine())
+	assert.Equal(2, source.GetCharPositionInLine())
+	eof := source.NextToken()
+	assert.Equal(2, eof.GetLine())
+	assert.Equal(2, eof.GetColumn())
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"os"
+	"syscall"
+)
+
+// MmapCharStream is a CharStream over a memory-mapped UTF-8 file. The file
+// is decoded lazily by the embedded UTF8InputStream, so opening even a very
+// large file costs no copy of its contents, and GetText only copies the
+// requested slice of the mapping.
+//
+// The mapping is released by Close. Token text is read from the mapping on
+// demand, so tokens must not be asked for their text after Close unless the
+// lexer used a TokenFactory that copies text, such as
+// NewCommonTokenFactory(true).
+type MmapCharStream struct {
+	*UTF8InputStream
+
+	filename string
+	mapped   []byte
+}
+
+// NewMmapCharStream maps fileName into memory read-only and returns a stream
+// over its contents.
+func NewMmapCharStream(fileName string) (*MmapCharStream, error) {
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	ms := new(MmapCharStream)
+
+	ms.filename = fileName
+	if fi.Size() > 0 {
+		// an empty file cannot be mapped and needs no mapping anyway
+		ms.mapped, err = syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+		if err != nil {
+			return nil, &os.PathError{Op: "mmap", Path: fileName, Err: err}
+		}
+	}
+	ms.UTF8InputStream = NewUTF8InputStream(ms.mapped)
+
+	return ms, nil
+}
+
+// Close unmaps the file. The stream must not be used afterwards.
+func (m *MmapCharStream) Close() error {
+	if m.mapped == nil {
+		return nil
+	}
+
+	err := syscall.Munmap(m.mapped)
+	m.mapped = nil
+	m.UTF8InputStream = NewUTF8InputStream(nil)
+	return err
+}
+
+func (m *MmapCharStream) GetSourceName() string {
+	return m.filename
+}
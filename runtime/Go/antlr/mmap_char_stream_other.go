@@ -0,0 +1,47 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+//go:build !linux
+// +build !linux
+
+package antlr
+
+import (
+	"io/ioutil"
+)
+
+// MmapCharStream is a CharStream over a UTF-8 file. On Linux the file is
+// memory-mapped; on other platforms it is read into memory once and decoded
+// lazily by the embedded UTF8InputStream.
+type MmapCharStream struct {
+	*UTF8InputStream
+
+	filename string
+}
+
+// NewMmapCharStream reads fileName and returns a stream over its contents.
+func NewMmapCharStream(fileName string) (*MmapCharStream, error) {
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	ms := new(MmapCharStream)
+
+	ms.filename = fileName
+	ms.UTF8InputStream = NewUTF8InputStream(data)
+
+	return ms, nil
+}
+
+// Close releases the file contents. The stream must not be used afterwards.
+func (m *MmapCharStream) Close() error {
+	m.UTF8InputStream = NewUTF8InputStream(nil)
+	return nil
+}
+
+func (m *MmapCharStream) GetSourceName() string {
+	return m.filename
+}
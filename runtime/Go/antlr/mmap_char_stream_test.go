@@ -0,0 +1,46 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMmapCharStream(t *testing.T) {
+	assert := assertNew(t)
+
+	dir, err := ioutil.TempDir("", "antlr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "input.txt")
+	if err := ioutil.WriteFile(fileName, []byte("abc = 42;"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := NewMmapCharStream(fileName)
+	assert.Nil(err)
+	assert.Equal(fileName, input.GetSourceName())
+
+	tokens := NewLexerB(input).GetAllTokens()
+	assert.Equal(6, len(tokens))
+	assert.Equal("abc", tokens[0].GetText())
+	assert.Equal("42", tokens[4].GetText())
+	assert.Nil(input.Close())
+
+	empty := filepath.Join(dir, "empty.txt")
+	if err := ioutil.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	input, err = NewMmapCharStream(empty)
+	assert.Nil(err)
+	assert.Equal(TokenEOF, input.LA(1))
+	assert.Nil(input.Close())
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"bytes"
+	"sort"
+)
+
+// SourceSegment is one named piece of input for a MultiSourceCharStream,
+// such as the contents of an included file.
+type SourceSegment struct {
+	Name string
+	Text string
+}
+
+// SourcePosition locates a character in the segment it came from. Line is
+// 1-based and Column is 0-based, as for tokens.
+type SourcePosition struct {
+	SourceName string
+	Line       int
+	Column     int
+}
+
+// MultiSourceCharStream is a CharStream over the concatenation of several
+// named segments, for example the files pulled together by a preprocessor
+// expanding #include directives.
+//
+// The lexer sees one continuous input, so token indexes, lines and columns
+// are relative to the concatenation. SourcePositionOf, ResolveLineColumn and
+// ResolveSyntaxError translate them back to the segment name, line and column
+// they originated from.
+//
+// A lexer reading the stream records the segment a token starts in as the
+// token's input stream, so token.GetInputStream().GetSourceName() is the name
+// of that segment. The stream's own GetSourceName does not depend on the
+// position and returns the name of the first segment.
+type MultiSourceCharStream struct {
+	*InputStream
+
+	names []string
+
+	// segmentStarts[i] is the index of the first character of segment i.
+	segmentStarts []int
+
+	// segments[i] is the input stream of the tokens starting in segment i.
+	segments []*segmentCharStream
+}
+
+// segmentedCharStream is implemented by input streams made of named pieces.
+// The lexer records the piece a token starts in as its input stream.
+type segmentedCharStream interface {
+	CharStream
+
+	// segmentStream returns the input stream to record on a token starting
+	// at index.
+	segmentStream(index int) CharStream
+}
+
+var _ segmentedCharStream = &MultiSourceCharStream{}
+
+// segmentCharStream is the input stream of the tokens starting in one
+// segment of a MultiSourceCharStream. It reads the whole concatenation, so
+// the start and stop indexes of tokens keep working, but is named after the
+// segment.
+type segmentCharStream struct {
+	*MultiSourceCharStream
+
+	name string
+}
+
+func (s *segmentCharStream) GetSourceName() string {
+	return s.name
+}
+
+// NewMultiSourceCharStream creates a stream over the concatenated text of
+// segments.
+func NewMultiSourceCharStream(segments ...SourceSegment) *MultiSourceCharStream {
+
+	ms := new(MultiSourceCharStream)
+
+	var buf bytes.Buffer
+	index := 0
+	for _, segment := range segments {
+		ms.names = append(ms.names, segment.Name)
+		ms.segmentStarts = append(ms.segmentStarts, index)
+		ms.segments = append(ms.segments, &segmentCharStream{ms, segment.Name})
+		buf.WriteString(segment.Text)
+		index += len([]rune(segment.Text))
+	}
+
+	ms.InputStream = NewInputStream(buf.String())
+
+	return ms
+}
+
+func (ms *MultiSourceCharStream) segmentStream(index int) CharStream {
+	if len(ms.segments) == 0 {
+		return ms
+	}
+
+	return ms.segments[ms.segmentOf(index)]
+}
+
+// segmentOf returns the segment of the character at index, clamped to the
+// stream.
+func (ms *MultiSourceCharStream) segmentOf(index int) int {
+	index = intMax(0, intMin(index, ms.size))
+	return sort.SearchInts(ms.segmentStarts, index+1) - 1
+}
+
+// SourcePositionOf returns the segment name, line and column of the character
+// at index.
+func (ms *MultiSourceCharStream) SourcePositionOf(index int) SourcePosition {
+	if len(ms.names) == 0 {
+		return SourcePosition{SourceName: ms.InputStream.GetSourceName(), Line: 1}
+	}
+	index = intMax(0, intMin(index, ms.size))
+
+	segment := ms.segmentOf(index)
+	segmentStart := ms.segmentStarts[segment]
+
+	lines := ms.GetLineIndex()
+	line, column := lines.PositionOf(index)
+	lineStart := intMax(index-column, segmentStart)
+
+	return SourcePosition{
+		SourceName: ms.names[segment],
+		Line:       line - lines.LineOf(segmentStart) + 1,
+		Column:     index - lineStart,
+	}
+}
+
+// ResolveLineColumn translates a line and column of the concatenation, as
+// reported by tokens and lexer errors, into a position in its segment.
+func (ms *MultiSourceCharStream) ResolveLineColumn(line, column int) SourcePosition {
+	lines := ms.GetLineIndex()
+	line = intMax(1, intMin(line, lines.LineCount()))
+	return ms.SourcePositionOf(lines.OffsetOf(line, intMax(0, column)))
+}
+
+// ResolveSyntaxError translates the location passed to
+// ErrorListener.SyntaxError into a position in its segment. Parsers pass the
+// offending token, whose start index is used; lexers pass no token, so line
+// and column are resolved instead.
+func (ms *MultiSourceCharStream) ResolveSyntaxError(offendingSymbol interface{}, line, column int) SourcePosition {
+	if t, ok := offendingSymbol.(Token); ok && t.GetStart() >= 0 {
+		return ms.SourcePositionOf(t.GetStart())
+	}
+
+	return ms.ResolveLineColumn(line, column)
+}
+
+// GetSourceName returns the name of the first segment, typically the main
+// file the others were included into. It does not follow the current
+// position: error listeners read it long after the character they report was
+// consumed. Tokens name their own segment through their input stream, and
+// ResolveSyntaxError gives the segment of a lexer error.
+func (ms *MultiSourceCharStream) GetSourceName() string {
+	if len(ms.names) == 0 {
+		return ms.InputStream.GetSourceName()
+	}
+
+	return ms.names[0]
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+func TestMultiSourceCharStreamPositions(t *testing.T) {
+	assert := assertNew(t)
+	input := NewMultiSourceCharStream(
+		SourceSegment{Name: "main.txt", Text: "a\nb"},
+		SourceSegment{Name: "inc.txt", Text: "cd\ne\n"},
+		SourceSegment{Name: "empty.txt"},
+		SourceSegment{Name: "tail.txt", Text: "f"},
+	)
+
+	assert.Equal("main.txt", input.GetSourceName())
+	assert.Equal(SourcePosition{"main.txt", 1, 0}, input.SourcePositionOf(0))
+	assert.Equal(SourcePosition{"main.txt", 2, 0}, input.SourcePositionOf(2))
+
+	// "cd" continues line 2 of the concatenation but starts inc.txt
+	assert.Equal(SourcePosition{"inc.txt", 1, 0}, input.SourcePositionOf(3))
+	assert.Equal(SourcePosition{"inc.txt", 1, 1}, input.SourcePositionOf(4))
+	assert.Equal(SourcePosition{"inc.txt", 2, 0}, input.SourcePositionOf(6))
+	assert.Equal(SourcePosition{"tail.txt", 1, 0}, input.SourcePositionOf(8))
+
+	assert.Equal(SourcePosition{"inc.txt", 1, 1}, input.ResolveLineColumn(2, 2))
+	assert.Equal(SourcePosition{"inc.txt", 2, 0}, input.ResolveLineColumn(3, 0))
+
+	// the source name does not follow the position
+	input.Seek(7)
+	assert.Equal("main.txt", input.GetSourceName())
+}
+
+func TestMultiSourceCharStreamSyntaxError(t *testing.T) {
+	assert := assertNew(t)
+	input := NewMultiSourceCharStream(
+		SourceSegment{Name: "a.txt", Text: "x = 1;"},
+		SourceSegment{Name: "b.txt", Text: "y = 2;"},
+	)
+	tokens := NewLexerB(input).GetAllTokens()
+
+	// tokens are named after the segment they start in, while the stream's
+	// name stays that of the first segment
+	x := tokens[0]
+	assert.Equal("a.txt", x.GetInputStream().GetSourceName())
+	assert.Equal("a.txt", input.GetSourceName())
+	assert.Equal(SourcePosition{"a.txt", 1, 0}, input.ResolveSyntaxError(x, x.GetLine(), x.GetColumn()))
+
+	y := tokens[6]
+	assert.Equal("y", y.GetText())
+	assert.Equal("b.txt", y.GetInputStream().GetSourceName())
+	assert.Equal(SourcePosition{"b.txt", 1, 0}, input.ResolveSyntaxError(y, y.GetLine(), y.GetColumn()))
+	assert.Equal(SourcePosition{"b.txt", 1, 4}, input.ResolveSyntaxError(nil, 1, 10))
+}
+
+func TestMultiSourceCharStreamTokenSourceNames(t *testing.T) {
+	assert := assertNew(t)
+	input := NewMultiSourceCharStream(
+		SourceSegment{Name: "main.txt", Text: "a=1;"},
+		SourceSegment{Name: "empty.txt"},
+		SourceSegment{Name: "inc.txt", Text: "bc;"},
+		SourceSegment{Name: "main.txt#2", Text: "d;"},
+	)
+	stream := NewCommonTokenStream(NewLexerB(input), TokenDefaultChannel)
+	stream.Fill()
+
+	var texts, names []string
+	for _, token := range stream.GetAllTokens() {
+		texts = append(texts, token.GetText())
+		names = append(names, token.GetInputStream().GetSourceName())
+	}
+	assert.Equal([]string{"a", "=", "1", ";", "bc", ";", "d", ";", "<EOF>"}, texts)
+	assert.Equal([]string{
+		"main.txt", "main.txt", "main.txt", "main.txt",
+		"inc.txt", "inc.txt",
+		"main.txt#2", "main.txt#2", "main.txt#2",
+	}, names)
+}
@@ -55,6 +55,12 @@ func NewBaseParser(input TokenStream) *BaseParser {
 	p := new(BaseParser)
 
 	p.BaseRecognizer = NewBaseRecognizer()
+	p.grammarATN = func() *ATN {
+		if p.Interpreter == nil {
+			return nil
+		}
+		return p.Interpreter.atn
+	}
 
 	// The input stream.
 	p.input = nil
@@ -99,7 +105,7 @@ func (p *BaseParser) reset() {
 	if p.input != nil {
 		p.input.Seek(0)
 	}
-	p.errHandler.reset(p)
+	p.errHandler.Reset(p)
 	p.ctx = nil
 	p._SyntaxErrors = 0
 	p.SetTrace(nil)
@@ -321,8 +327,8 @@ func (p *BaseParser) GetTokenFactory() TokenFactory {
 }
 
 // Tell our token source and error strategy about a Newway to create tokens.//
-func (p *BaseParser) setTokenFactory(factory TokenFactory) {
-	p.input.GetTokenSource().setTokenFactory(factory)
+func (p *BaseParser) SetTokenFactory(factory TokenFactory) {
+	p.input.GetTokenSource().SetTokenFactory(factory)
 }
 
 // The ATN with bypass alternatives is expensive to create so we create it
@@ -425,7 +431,7 @@ func (p *BaseParser) Consume() Token {
 	}
 	hasListener := p.parseListeners != nil && len(p.parseListeners) > 0
 	if p.BuildParseTrees || hasListener {
-		if p.errHandler.inErrorRecoveryMode(p) {
+		if p.errHandler.InErrorRecoveryMode(p) {
 			node := p.ctx.AddErrorNode(o)
 			if p.parseListeners != nil {
 				for _, l := range p.parseListeners {
@@ -545,7 +551,9 @@ func (p *BaseParser) UnrollRecursionContexts(parentCtx ParserRuleContext) {
 	if p.parseListeners != nil {
 		for p.ctx != parentCtx {
 			p.TriggerExitRuleEvent()
-			p.ctx = p.ctx.GetParent().(ParserRuleContext)
+			// the root context of a left-recursive start rule has no parent
+			parent, _ := p.ctx.GetParent().(ParserRuleContext)
+			p.ctx = parent
 		}
 	} else {
 		p.ctx = parentCtx
@@ -691,7 +699,7 @@ func (p *BaseParser) DumpDFA() {
 				fmt.Println()
 			}
 			fmt.Println("Decision " + strconv.Itoa(dfa.decision) + ":")
-			fmt.Print(dfa.String(p.LiteralNames, p.SymbolicNames))
+			fmt.Print(dfa.String(p.GetVocabulary()))
 			seenOne = true
 		}
 	}
@@ -7,7 +7,6 @@ package antlr
 import (
 	"fmt"
 	"strconv"
-	"strings"
 )
 
 var (
@@ -145,7 +144,7 @@ func (p *ParserATNSimulator) AdaptivePredict(input TokenStream, decision int, ou
 	}
 	alt := p.execATN(dfa, s0, input, index, outerContext)
 	if ParserATNSimulatorDebug {
-		fmt.Println("DFA after predictATN: " + dfa.String(p.parser.GetLiteralNames(), nil))
+		fmt.Println("DFA after predictATN: " + dfa.String(p.parser.GetVocabulary()))
 	}
 	return alt
 
@@ -1288,12 +1287,10 @@ func (p *ParserATNSimulator) GetTokenName(t int) string {
 		return "EOF"
 	}
 
-	if p.parser != nil && p.parser.GetLiteralNames() != nil {
-		if t >= len(p.parser.GetLiteralNames()) {
-			fmt.Println(strconv.Itoa(t) + " ttype out of range: " + strings.Join(p.parser.GetLiteralNames(), ","))
-			//			fmt.Println(p.parser.GetInputStream().(TokenStream).GetAllText()) // p seems incorrect
-		} else {
-			return p.parser.GetLiteralNames()[t] + "<" + strconv.Itoa(t) + ">"
+	if p.parser != nil {
+		vocabulary := p.parser.GetVocabulary()
+		if t <= vocabulary.MaxTokenType() {
+			return vocabulary.GetDisplayName(t) + "<" + strconv.Itoa(t) + ">"
 		}
 	}
 
@@ -1391,12 +1388,12 @@ func (p *ParserATNSimulator) addDFAEdge(dfa *DFA, from *DFAState, t int, to *DFA
 	from.edges[t+1] = to // connect
 
 	if ParserATNSimulatorDebug {
-		var names []string
+		var vocabulary Vocabulary
 		if p.parser != nil {
-			names = p.parser.GetLiteralNames()
+			vocabulary = p.parser.GetVocabulary()
 		}
 
-		fmt.Println("DFA=\n" + dfa.String(names, nil))
+		fmt.Println("DFA=\n" + dfa.String(vocabulary))
 	}
 	return to
 }
@@ -0,0 +1,292 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"strconv"
+)
+
+// ParserInterpreter is a Parser that walks a parser ATN directly, without
+// code generated for the grammar. It predicts alternatives with the same
+// ParserATNSimulator as a generated parser, reports and recovers from errors
+// with its ErrorStrategy, and builds a parse tree of
+// BaseInterpreterRuleContext nodes.
+//
+// Embedded actions are ignored and semantic predicates evaluate to true, as
+// their code is only available in the target language. Precedence
+// predicates of left-recursive rules are evaluated as usual.
+type ParserInterpreter struct {
+	*BaseParser
+
+	atn *ATN
+
+	// parentContextStack holds, for each left-recursive rule being parsed,
+	// the context and invoking state it was entered from.
+	parentContextStack []parentContext
+
+	overrideDecision           int
+	overrideDecisionInputIndex int
+	overrideDecisionAlt        int
+	overrideDecisionReached    bool
+
+	rootContext *BaseInterpreterRuleContext
+}
+
+type parentContext struct {
+	ctx           ParserRuleContext
+	invokingState int
+}
+
+// NewParserInterpreter creates a parser for atn, which must be a parser ATN.
+// vocabulary names the token types and ruleNames the parser rules in ATN
+// order.
+func NewParserInterpreter(grammarFileName string, vocabulary Vocabulary, ruleNames []string, atn *ATN, input TokenStream) *ParserInterpreter {
+	if atn.grammarType != ATNTypeParser {
+		panic("The ATN must be a parser ATN.")
+	}
+
+	p := new(ParserInterpreter)
+
+	p.BaseParser = NewBaseParser(input)
+	p.atn = atn
+	p.overrideDecision = -1
+	p.overrideDecisionInputIndex = -1
+	p.overrideDecisionAlt = -1
+
+	decisionToDFA := make([]*DFA, len(atn.DecisionToState))
+	for i, ds := range atn.DecisionToState {
+		decisionToDFA[i] = NewDFA(ds, i)
+	}
+	p.Interpreter = NewParserATNSimulator(p, atn, decisionToDFA, NewPredictionContextCache())
+
+	p.GrammarFileName = grammarFileName
+	p.RuleNames = ruleNames
+	p.setVocabulary(vocabulary)
+
+	return p
+}
+
+// Parse parses the input starting with the rule at startRuleIndex and
+// returns the resulting parse tree. A decision override set with
+// AddDecisionOverride applies once per call.
+func (p *ParserInterpreter) Parse(startRuleIndex int) ParserRuleContext {
+	p.overrideDecisionReached = false
+	p.parentContextStack = p.parentContextStack[:0]
+
+	startRuleStartState := p.atn.ruleToStartState[startRuleIndex]
+
+	p.rootContext = p.createInterpreterRuleContext(nil, ATNStateInvalidStateNumber, startRuleIndex)
+	if startRuleStartState.isPrecedenceRule {
+		p.EnterRecursionRule(p.rootContext, startRuleStartState.GetStateNumber(), startRuleIndex, 0)
+	} else {
+		p.EnterRule(p.rootContext, startRuleStartState.GetStateNumber(), startRuleIndex)
+	}
+
+	for {
+		s := p.getATNState()
+		if s.GetStateType() != ATNStateRuleStop {
+			p.safeVisitState(s)
+			continue
+		}
+
+		// pop; return from rule
+		if p.ctx.IsEmpty() {
+			if startRuleStartState.isPrecedenceRule {
+				result := p.ctx
+				parent := p.popParentContext()
+				p.UnrollRecursionContexts(parent.ctx)
+				return result
+			}
+
+			p.ExitRule()
+			return p.rootContext
+		}
+
+		p.visitRuleStopState(s)
+	}
+}
+
+// AddDecisionOverride forces the parser to predict forcedAlt at decision
+// when the current token index is tokenIndex, instead of asking the ATN
+// simulator. Tools use it to explore the alternative interpretations of an
+// ambiguous input. Only one override is active at a time.
+func (p *ParserInterpreter) AddDecisionOverride(decision, tokenIndex, forcedAlt int) {
+	p.overrideDecision = decision
+	p.overrideDecisionInputIndex = tokenIndex
+	p.overrideDecisionAlt = forcedAlt
+}
+
+// GetRootContext returns the context of the start rule of the last parse.
+func (p *ParserInterpreter) GetRootContext() InterpreterRuleContext {
+	return p.rootContext
+}
+
+// GetATN returns the ATN the parser walks.
+func (p *ParserInterpreter) GetATN() *ATN {
+	return p.atn
+}
+
+// Action ignores embedded actions, which cannot be interpreted.
+func (p *ParserInterpreter) Action(localctx RuleContext, ruleIndex, actionIndex int) {
+}
+
+// EnterRecursionRule records the context that entered a left-recursive rule
+// so that visitRuleStopState can return to it.
+func (p *ParserInterpreter) EnterRecursionRule(localctx ParserRuleContext, state, ruleIndex, precedence int) {
+	p.parentContextStack = append(p.parentContextStack, parentContext{p.ctx, localctx.GetInvokingState()})
+	p.BaseParser.EnterRecursionRule(localctx, state, ruleIndex, precedence)
+}
+
+func (p *ParserInterpreter) popParentContext() parentContext {
+	parent := p.parentContextStack[len(p.parentContextStack)-1]
+	p.parentContextStack = p.parentContextStack[:len(p.parentContextStack)-1]
+	return parent
+}
+
+func (p *ParserInterpreter) getATNState() ATNState {
+	return p.atn.states[p.GetState()]
+}
+
+// safeVisitState visits s, and reports and recovers from a recognition error
+// the way generated rule functions do.
+func (p *ParserInterpreter) safeVisitState(s ATNState) {
+	defer func() {
+		if e := recover(); e != nil {
+			re, ok := e.(RecognitionException)
+			if !ok {
+				panic(e)
+			}
+
+			p.SetState(p.atn.ruleToStopState[s.GetRuleIndex()].GetStateNumber())
+			p.ctx.SetException(re)
+			p.errHandler.ReportError(p, re)
+			p.recover(re)
+		}
+	}()
+
+	p.visitState(s)
+}
+
+func (p *ParserInterpreter) visitState(s ATNState) {
+	predictedAlt := 1
+	if ds, ok := s.(DecisionState); ok {
+		predictedAlt = p.visitDecisionState(ds)
+	}
+
+	transition := s.GetTransitions()[predictedAlt-1]
+	switch transition.getSerializationType() {
+	case TransitionEPSILON:
+		if entry, ok := s.(*StarLoopEntryState); ok && entry.precedenceRuleDecision {
+			if _, ok := transition.getTarget().(*LoopEndState); !ok {
+				// We are at the start of a left recursive rule's (...)* loop
+				// and we're not taking the exit branch of loop.
+				parent := p.parentContextStack[len(p.parentContextStack)-1]
+				localctx := p.createInterpreterRuleContext(parent.ctx, parent.invokingState, p.ctx.GetRuleIndex())
+				p.PushNewRecursionContext(localctx, p.atn.ruleToStartState[s.GetRuleIndex()].GetStateNumber(), p.ctx.GetRuleIndex())
+			}
+		}
+
+	case TransitionATOM:
+		p.Match(transition.(*AtomTransition).label)
+
+	case TransitionRANGE, TransitionSET, TransitionNOTSET:
+		if !transition.Matches(p.input.LA(1), TokenMinUserTokenType, 65535) {
+			p.errHandler.RecoverInline(p)
+		}
+		p.MatchWildcard()
+
+	case TransitionWILDCARD:
+		p.MatchWildcard()
+
+	case TransitionRULE:
+		ruleStartState := transition.getTarget().(*RuleStartState)
+		ruleIndex := ruleStartState.GetRuleIndex()
+		newctx := p.createInterpreterRuleContext(p.ctx, s.GetStateNumber(), ruleIndex)
+		if ruleStartState.isPrecedenceRule {
+			p.EnterRecursionRule(newctx, ruleStartState.GetStateNumber(), ruleIndex, transition.(*RuleTransition).precedence)
+		} else {
+			p.EnterRule(newctx, transition.getTarget().GetStateNumber(), ruleIndex)
+		}
+
+	case TransitionPREDICATE:
+		predicate := transition.(*PredicateTransition)
+		if !p.Sempred(p.ctx, predicate.ruleIndex, predicate.predIndex) {
+			panic(NewFailedPredicateException(p, "", ""))
+		}
+
+	case TransitionACTION:
+		action := transition.(*ActionTransition)
+		p.Action(p.ctx, action.ruleIndex, action.actionIndex)
+
+	case TransitionPRECEDENCE:
+		precedence := transition.(*PrecedencePredicateTransition).precedence
+		if !p.Precpred(p.ctx, precedence) {
+			panic(NewFailedPredicateException(p, "precpred(p.GetParserRuleContext(), "+strconv.Itoa(precedence)+")", ""))
+		}
+
+	default:
+		panic("Unrecognized ATN transition type.")
+	}
+
+	p.SetState(transition.getTarget().GetStateNumber())
+}
+
+func (p *ParserInterpreter) visitDecisionState(s DecisionState) int {
+	if len(s.GetTransitions()) <= 1 {
+		return 1
+	}
+
+	p.errHandler.Sync(p)
+	decision := s.getDecision()
+	if decision == p.overrideDecision && p.input.Index() == p.overrideDecisionInputIndex && !p.overrideDecisionReached {
+		p.overrideDecisionReached = true
+		return p.overrideDecisionAlt
+	}
+
+	return p.Interpreter.AdaptivePredict(p.input, decision, p.ctx)
+}
+
+func (p *ParserInterpreter) createInterpreterRuleContext(parent ParserRuleContext, invokingStateNumber, ruleIndex int) *BaseInterpreterRuleContext {
+	return NewBaseInterpreterRuleContext(parent, invokingStateNumber, ruleIndex)
+}
+
+func (p *ParserInterpreter) visitRuleStopState(s ATNState) {
+	ruleStartState := p.atn.ruleToStartState[s.GetRuleIndex()]
+	if ruleStartState.isPrecedenceRule {
+		parent := p.popParentContext()
+		p.UnrollRecursionContexts(parent.ctx)
+		p.SetState(parent.invokingState)
+	} else {
+		p.ExitRule()
+	}
+
+	ruleTransition := p.getATNState().GetTransitions()[0].(*RuleTransition)
+	p.SetState(ruleTransition.followState.GetStateNumber())
+}
+
+// recover lets the error strategy recover from e. If that consumed no input,
+// an error node standing for the offending token is added to the tree so the
+// error is still visible in it.
+func (p *ParserInterpreter) recover(e RecognitionException) {
+	i := p.input.Index()
+	p.errHandler.Recover(p, e)
+	if p.input.Index() != i {
+		return
+	}
+
+	tok := e.GetOffendingToken()
+	expectedTokenType := TokenInvalidType
+	if ime, ok := e.(*InputMisMatchException); ok {
+		if expected := ime.getExpectedTokens(); expected != nil {
+			expectedTokenType = expected.first() // get any element
+		}
+	}
+
+	source := &TokenSourceCharStreamPair{tok.GetTokenSource(), tok.GetInputStream()}
+	errToken := p.GetTokenFactory().Create(source, expectedTokenType, tok.GetText(), TokenDefaultChannel,
+		-1, -1, // invalid start/stop
+		tok.GetLine(), tok.GetColumn(), tok.GetEndLine(), tok.GetEndColumn())
+	p.ctx.AddErrorNode(errToken)
+}
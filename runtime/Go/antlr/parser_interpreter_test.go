@@ -0,0 +1,123 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+type syntaxErrorCounter struct {
+	*DefaultErrorListener
+	errors []string
+}
+
+func (c *syntaxErrorCounter) SyntaxError(recognizer Recognizer, offendingSymbol interface{}, line, column int, msg string, e RecognitionException) {
+	c.errors = append(c.errors, msg)
+}
+
+func newParserPInterpreter(src string) (*ParserInterpreter, *syntaxErrorCounter) {
+	tokens := NewCommonTokenStream(NewLexerB(NewInputStream(src)), TokenDefaultChannel)
+	vocabulary := NewVocabulary(lexerB_lexerLiteralNames, lexerB_lexerSymbolicNames)
+	p := NewParserInterpreter("ParserP.g4", vocabulary, parserP_ruleNames, parserP_atn, tokens)
+	errors := &syntaxErrorCounter{DefaultErrorListener: NewDefaultErrorListener()}
+	p.RemoveErrorListeners()
+	p.AddErrorListener(errors)
+	return p, errors
+}
+
+func TestParserInterpreter(t *testing.T) {
+	assert := assertNew(t)
+	p, errors := newParserPInterpreter("a=1+2*b;c;")
+
+	tree := p.Parse(ParserPRULE_s)
+
+	assert.Equal(0, len(errors.errors))
+	assert.Equal(tree, p.GetRootContext())
+	assert.Equal("(s (stat a = (expr (expr 1) + (expr (expr 2) * (expr b))) ;) (stat (expr c) ;) <EOF>)",
+		tree.ToStringTree(nil, p))
+	assert.Nil(tree.GetParent())
+	assert.Equal(ParserPRULE_stat, tree.GetChild(0).(RuleContext).GetRuleIndex())
+	assert.Equal("a", tree.GetStart().GetText())
+	assert.Equal(LexerBSEMI, tree.GetStop().GetTokenType())
+	assert.Equal(2, p.GetRuleIndexMap()["expr"])
+}
+
+func TestParserInterpreterPrecedenceStartRule(t *testing.T) {
+	assert := assertNew(t)
+	p, errors := newParserPInterpreter("1*2+3")
+
+	tree := p.Parse(ParserPRULE_expr)
+
+	assert.Equal(0, len(errors.errors))
+	assert.Equal("(expr (expr (expr 1) * (expr 2)) + (expr 3))", tree.ToStringTree(nil, p))
+	assert.Equal("3", tree.GetStop().GetText())
+}
+
+// ruleEventRecorder records the rules a parse listener enters and exits.
+type ruleEventRecorder struct {
+	*BaseParseTreeListener
+	events []string
+}
+
+func (r *ruleEventRecorder) EnterEveryRule(ctx ParserRuleContext) {
+	r.events = append(r.events, "enter "+parserP_ruleNames[ctx.GetRuleIndex()])
+}
+
+func (r *ruleEventRecorder) ExitEveryRule(ctx ParserRuleContext) {
+	r.events = append(r.events, "exit "+parserP_ruleNames[ctx.GetRuleIndex()])
+}
+
+func TestParserInterpreterPrecedenceStartRuleListener(t *testing.T) {
+	assert := assertNew(t)
+	p, errors := newParserPInterpreter("1*2+3")
+	listener := &ruleEventRecorder{BaseParseTreeListener: new(BaseParseTreeListener)}
+	p.AddParseListener(listener)
+
+	tree := p.Parse(ParserPRULE_expr)
+
+	assert.Equal(0, len(errors.errors))
+	assert.Equal("(expr (expr (expr 1) * (expr 2)) + (expr 3))", tree.ToStringTree(nil, p))
+	assert.Nil(tree.GetParent())
+	// as in generated parsers, a context pushed for a recursion is entered,
+	// and unrolling exits the contexts up to the caller, here the root
+	assert.Equal([]string{
+		"enter expr", "enter expr", "enter expr", "exit expr",
+		"enter expr", "enter expr", "exit expr", "exit expr",
+	}, listener.events)
+}
+
+func TestParserInterpreterDecisionOverride(t *testing.T) {
+	assert := assertNew(t)
+
+	// without the override, stat predicts its second alternative for "a;"
+	p, errors := newParserPInterpreter("a;")
+	tree := p.Parse(ParserPRULE_s)
+	assert.Equal("(s (stat (expr a) ;) <EOF>)", tree.ToStringTree(nil, p))
+
+	// decision 2 is the choice between the alternatives of stat
+	p, errors = newParserPInterpreter("a;")
+	p.AddDecisionOverride(2, 0, 1)
+	tree = p.Parse(ParserPRULE_s)
+	assert.Equal([]string{"mismatched input ';' expecting '='"}, errors.errors)
+	assert.Equal("(s (stat a ;) <EOF>)", tree.ToStringTree(nil, p))
+}
+
+func TestParserInterpreterSyntaxError(t *testing.T) {
+	assert := assertNew(t)
+	p, errors := newParserPInterpreter("a=;")
+
+	tree := p.Parse(ParserPRULE_s)
+
+	assert.Equal([]string{"mismatched input ';' expecting {ID, INT}"}, errors.errors)
+	assert.Equal(ParserPRULE_s, tree.GetRuleIndex())
+}
+
+func TestParserInterpreterLexerATN(t *testing.T) {
+	assert := assertNew(t)
+
+	assert.Panics(func() {
+		NewParserInterpreter("LexerB.g4", VocabularyEMPTY, lexerB_lexerRuleNames, lexerB_lexerAtn, nil)
+	})
+}
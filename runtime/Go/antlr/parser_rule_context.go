@@ -346,11 +346,14 @@ type InterpreterRuleContext interface {
 	ParserRuleContext
 }
 
+// BaseInterpreterRuleContext is the parse tree node built by a
+// ParserInterpreter. Unlike generated contexts, which know their rule from
+// their type, it records the index of its rule.
 type BaseInterpreterRuleContext struct {
 	*BaseParserRuleContext
 }
 
-func NewBaseInterpreterRuleContext(parent BaseInterpreterRuleContext, invokingStateNumber, ruleIndex int) *BaseInterpreterRuleContext {
+func NewBaseInterpreterRuleContext(parent ParserRuleContext, invokingStateNumber, ruleIndex int) *BaseInterpreterRuleContext {
 
 	prc := new(BaseInterpreterRuleContext)
 
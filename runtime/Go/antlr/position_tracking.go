@@ -0,0 +1,67 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+// PositionTracking configures how a lexer computes the line and column of
+// each character it consumes, which become the line and column of tokens and
+// lexer errors. Install it with BaseLexer.SetPositionTracking.
+type PositionTracking struct {
+	// TabWidth is the distance between tab stops. A tab moves the column to
+	// the next multiple of TabWidth. Values below 2 make a tab one column
+	// wide like any other character.
+	TabWidth int
+
+	// LineTerminators lists the characters that end a line, for example
+	// '\n', '\r' and '\u2028'. When empty, only '\n' ends a line.
+	LineTerminators []rune
+
+	// CRLFAsOneBreak makes "\r\n" a single line break when both '\r' and
+	// '\n' are line terminators.
+	CRLFAsOneBreak bool
+}
+
+// NewPositionTracking returns tracking that treats '\n', lone '\r', "\r\n",
+// U+2028 and U+2029 as line breaks and places tab stops every tabWidth
+// columns.
+func NewPositionTracking(tabWidth int) *PositionTracking {
+	return &PositionTracking{
+		TabWidth:        tabWidth,
+		LineTerminators: []rune{'\n', '\r', '\u2028', '\u2029'},
+		CRLFAsOneBreak:  true,
+	}
+}
+
+// advance returns the line and column that follow the character c at line
+// and column. prev is the character before c, or TokenEOF.
+func (p *PositionTracking) advance(line, column, c, prev int) (int, int) {
+	if p.isLineTerminator(c) {
+		if c == '\n' && prev == '\r' && p.CRLFAsOneBreak && p.isLineTerminator('\r') {
+			// the '\r' already started the new line
+			return line, column
+		}
+
+		return line + 1, 0
+	}
+
+	if c == '\t' && p.TabWidth > 1 {
+		return line, (column/p.TabWidth + 1) * p.TabWidth
+	}
+
+	return line, column + 1
+}
+
+func (p *PositionTracking) isLineTerminator(c int) bool {
+	if len(p.LineTerminators) == 0 {
+		return c == '\n'
+	}
+
+	for _, t := range p.LineTerminators {
+		if int(t) == c {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+// consumePositions consumes all of input with the position tracking of
+// lexer and returns the line and column before each character.
+func consumePositions(lexer *LexerB, input CharStream) [][2]int {
+	var positions [][2]int
+	for input.LA(1) != TokenEOF {
+		positions = append(positions, [2]int{lexer.GetLine(), lexer.GetCharPositionInLine()})
+		lexer.Interpreter.Consume(input)
+	}
+
+	return append(positions, [2]int{lexer.GetLine(), lexer.GetCharPositionInLine()})
+}
+
+func TestPositionTrackingDefault(t *testing.T) {
+	assert := assertNew(t)
+	input := NewInputStream("a\tb\rc\r\nd")
+	lexer := NewLexerB(input)
+
+	assert.Equal([][2]int{
+		{1, 0}, {1, 1}, {1, 2}, {1, 3}, {1, 4}, {1, 5}, {1, 6}, {2, 0}, {2, 1},
+	}, consumePositions(lexer, input))
+}
+
+func TestPositionTrackingConfigured(t *testing.T) {
+	assert := assertNew(t)
+	input := NewInputStream("a\tb\rc\r\nd\u2028e\n\n")
+	lexer := NewLexerB(input)
+	lexer.SetPositionTracking(NewPositionTracking(4))
+
+	assert.Equal([][2]int{
+		{1, 0}, {1, 1}, {1, 4}, {1, 5}, // a \t b \r
+		{2, 0}, {2, 1}, {3, 0}, // c \r \n
+		{3, 0}, {3, 1}, // d \u2028
+		{4, 0}, {4, 1}, {5, 0}, // e \n \n
+		{6, 0},
+	}, consumePositions(lexer, input))
+}
+
+func TestPositionTrackingSeparateCRLF(t *testing.T) {
+	assert := assertNew(t)
+	input := NewInputStream("a\r\nb")
+	lexer := NewLexerB(input)
+	lexer.SetPositionTracking(&PositionTracking{LineTerminators: []rune{'\r', '\n'}})
+
+	assert.Equal([][2]int{
+		{1, 0}, {1, 1}, {2, 0}, {3, 0}, {3, 1},
+	}, consumePositions(lexer, input))
+}
+
+// plainLexerATNSimulator only has the methods of ILexerATNSimulator, like a
+// simulator implemented outside this package.
+type plainLexerATNSimulator struct {
+	ILexerATNSimulator
+}
+
+func TestPositionTrackingOptional(t *testing.T) {
+	assert := assertNew(t)
+	input := NewInputStream("ab cd")
+	lexer := NewLexerB(input)
+	lexer.Interpreter = plainLexerATNSimulator{lexer.Interpreter}
+
+	assert.Equal("ab", lexer.NextToken().GetText())
+	assert.Equal(2, lexer.GetCharPositionInLine())
+	assert.Panics(func() { lexer.SetPositionTracking(NewPositionTracking(4)) })
+}
@@ -7,6 +7,7 @@ package antlr
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"strconv"
 )
@@ -15,6 +16,7 @@ type Recognizer interface {
 	GetLiteralNames() []string
 	GetSymbolicNames() []string
 	GetRuleNames() []string
+	GetVocabulary() Vocabulary
 
 	Sempred(RuleContext, int, int) bool
 	Precpred(RuleContext, int) bool
@@ -36,6 +38,20 @@ type BaseRecognizer struct {
 	LiteralNames    []string
 	SymbolicNames   []string
 	GrammarFileName string
+
+	// vocabulary is looked up from LiteralNames and SymbolicNames on first
+	// use.
+	vocabulary *BaseVocabulary
+
+	// ruleIndexMap is looked up from RuleNames on first use and is only
+	// valid while RuleNames is ruleIndexMapNames.
+	ruleIndexMap      map[string]int
+	ruleIndexMapNames namesKey
+
+	// grammarATN returns the ATN of the recognizer, whose nameMaps cache
+	// the maps for all recognizers of the grammar. It is set by the
+	// embedding BaseLexer or BaseParser.
+	grammarATN func() *ATN
 }
 
 func NewBaseRecognizer() *BaseRecognizer {
@@ -45,8 +61,50 @@ func NewBaseRecognizer() *BaseRecognizer {
 	return rec
 }
 
-var tokenTypeMapCache = make(map[string]int)
-var ruleIndexMapCache = make(map[string]int)
+// nameMaps caches the vocabularies and rule index maps built for the
+// recognizers of a grammar. It lives in the grammar's ATN, which all those
+// recognizers share, so each grammar builds its maps once, whichever
+// goroutine gets there first, and they are freed with the grammar. The maps
+// are keyed by the identity of the name slices they are built from: in
+// generated code those are package-level variables of the grammar.
+type nameMaps struct {
+	mu            sync.Mutex
+	vocabularies  map[vocabularyCacheKey]*BaseVocabulary
+	ruleIndexMaps map[namesKey]map[string]int
+}
+
+type vocabularyCacheKey struct {
+	literalNames  namesKey
+	symbolicNames namesKey
+}
+
+// namesKey identifies a slice of names by its first element and length, so
+// that slices sharing a backing array are told apart.
+type namesKey struct {
+	first *string
+	len   int
+}
+
+func newNamesKey(names []string) namesKey {
+	if len(names) == 0 {
+		return namesKey{}
+	}
+
+	return namesKey{&names[0], len(names)}
+}
+
+// getNameMaps returns the cache of the recognizer's grammar, or nil if the
+// recognizer has no ATN.
+func (b *BaseRecognizer) getNameMaps() *nameMaps {
+	if b.grammarATN == nil {
+		return nil
+	}
+	if atn := b.grammarATN(); atn != nil {
+		return &atn.nameMaps
+	}
+
+	return nil
+}
 
 func (b *BaseRecognizer) checkVersion(toolVersion string) {
 	runtimeVersion := "4.7.2"
@@ -71,6 +129,9 @@ func (b *BaseRecognizer) GetRuleNames() []string {
 	return b.RuleNames
 }
 
+// GetTokenNames returns the literal names of the recognizer's tokens.
+//
+// Deprecated: use GetVocabulary, which also covers symbolic names.
 func (b *BaseRecognizer) GetTokenNames() []string {
 	return b.LiteralNames
 }
@@ -83,6 +144,40 @@ func (b *BaseRecognizer) GetLiteralNames() []string {
 	return b.LiteralNames
 }
 
+// GetVocabulary returns the vocabulary of the recognizer's token types, built
+// from LiteralNames and SymbolicNames.
+func (b *BaseRecognizer) GetVocabulary() Vocabulary {
+	return b.getVocabulary()
+}
+
+func (b *BaseRecognizer) getVocabulary() *BaseVocabulary {
+	if b.vocabulary != nil {
+		return b.vocabulary
+	}
+
+	cache := b.getNameMaps()
+	if cache == nil {
+		b.vocabulary = NewVocabulary(b.LiteralNames, b.SymbolicNames)
+		return b.vocabulary
+	}
+
+	key := vocabularyCacheKey{newNamesKey(b.LiteralNames), newNamesKey(b.SymbolicNames)}
+
+	cache.mu.Lock()
+	vocabulary, ok := cache.vocabularies[key]
+	if !ok {
+		if cache.vocabularies == nil {
+			cache.vocabularies = make(map[vocabularyCacheKey]*BaseVocabulary)
+		}
+		vocabulary = NewVocabulary(b.LiteralNames, b.SymbolicNames)
+		cache.vocabularies[key] = vocabulary
+	}
+	cache.mu.Unlock()
+
+	b.vocabulary = vocabulary
+	return vocabulary
+}
+
 func (b *BaseRecognizer) GetState() int {
 	return b.state
 }
@@ -91,77 +186,82 @@ func (b *BaseRecognizer) SetState(v int) {
 	b.state = v
 }
 
-//func (b *Recognizer) GetTokenTypeMap() {
-//    var tokenNames = b.GetTokenNames()
-//    if (tokenNames==nil) {
-//        panic("The current recognizer does not provide a list of token names.")
-//    }
-//    var result = tokenTypeMapCache[tokenNames]
-//    if(result==nil) {
-//        result = tokenNames.reduce(function(o, k, i) { o[k] = i })
-//        result.EOF = TokenEOF
-//        tokenTypeMapCache[tokenNames] = result
-//    }
-//    return result
-//}
-
-// Get a map from rule names to rule indexes.
+// setVocabulary makes vocabulary the recognizer's vocabulary and fills in
+// LiteralNames and SymbolicNames to match. Interpreters use it in place of
+// the name slices of generated code.
+func (b *BaseRecognizer) setVocabulary(vocabulary Vocabulary) {
+	n := vocabulary.MaxTokenType() + 1
+	b.LiteralNames = make([]string, n)
+	b.SymbolicNames = make([]string, n)
+	for i := 0; i < n; i++ {
+		b.LiteralNames[i] = vocabulary.GetLiteralName(i)
+		b.SymbolicNames[i] = vocabulary.GetSymbolicName(i)
+	}
+
+	if v, ok := vocabulary.(*BaseVocabulary); ok {
+		b.vocabulary = v
+	} else {
+		b.vocabulary = NewVocabulary(b.LiteralNames, b.SymbolicNames)
+	}
+}
+
+// GetTokenTypeMap returns a map from the literal and symbolic names of the
+// recognizer's tokens to their token types, including "EOF" for TokenEOF.
 //
-// <p>Used for XPath and tree pattern compilation.</p>
+// The map is shared by all recognizers of the grammar and must not be
+// modified.
+func (b *BaseRecognizer) GetTokenTypeMap() map[string]int {
+	return b.getVocabulary().tokenTypes
+}
+
+// GetRuleIndexMap returns a map from rule names to rule indexes.
 //
+// The map is shared by all recognizers of the grammar and must not be
+// modified. Used for XPath and tree pattern compilation.
 func (b *BaseRecognizer) GetRuleIndexMap() map[string]int {
+	key := newNamesKey(b.RuleNames)
+	if b.ruleIndexMap != nil && b.ruleIndexMapNames == key {
+		return b.ruleIndexMap
+	}
+
+	var result map[string]int
+	if cache := b.getNameMaps(); cache != nil {
+		cache.mu.Lock()
+		result = cache.ruleIndexMaps[key]
+		if result == nil {
+			if cache.ruleIndexMaps == nil {
+				cache.ruleIndexMaps = make(map[namesKey]map[string]int)
+			}
+			result = newRuleIndexMap(b.RuleNames)
+			cache.ruleIndexMaps[key] = result
+		}
+		cache.mu.Unlock()
+	} else {
+		result = newRuleIndexMap(b.RuleNames)
+	}
 
-	panic("Method not defined!")
-	//    var ruleNames = b.GetRuleNames()
-	//    if (ruleNames==nil) {
-	//        panic("The current recognizer does not provide a list of rule names.")
-	//    }
-	//
-	//    var result = ruleIndexMapCache[ruleNames]
-	//    if(result==nil) {
-	//        result = ruleNames.reduce(function(o, k, i) { o[k] = i })
-	//        ruleIndexMapCache[ruleNames] = result
-	//    }
-	//    return result
+	b.ruleIndexMap, b.ruleIndexMapNames = result, key
+	return result
 }
 
-func (b *BaseRecognizer) GetTokenType(tokenName string) int {
-	panic("Method not defined!")
-	//    var ttype = b.GetTokenTypeMap()[tokenName]
-	//    if (ttype !=nil) {
-	//        return ttype
-	//    } else {
-	//        return TokenInvalidType
-	//    }
+func newRuleIndexMap(ruleNames []string) map[string]int {
+	result := make(map[string]int, len(ruleNames))
+	for i, name := range ruleNames {
+		result[name] = i
+	}
+
+	return result
 }
 
-//func (b *Recognizer) GetTokenTypeMap() map[string]int {
-//    Vocabulary vocabulary = getVocabulary()
-//
-//    Synchronized (tokenTypeMapCache) {
-//        Map<String, Integer> result = tokenTypeMapCache.Get(vocabulary)
-//        if (result == null) {
-//            result = new HashMap<String, Integer>()
-//            for (int i = 0; i < GetATN().maxTokenType; i++) {
-//                String literalName = vocabulary.getLiteralName(i)
-//                if (literalName != null) {
-//                    result.put(literalName, i)
-//                }
-//
-//                String symbolicName = vocabulary.GetSymbolicName(i)
-//                if (symbolicName != null) {
-//                    result.put(symbolicName, i)
-//                }
-//            }
-//
-//            result.put("EOF", Token.EOF)
-//            result = Collections.unmodifiableMap(result)
-//            tokenTypeMapCache.put(vocabulary, result)
-//        }
-//
-//        return result
-//    }
-//}
+// GetTokenType returns the token type with the given literal or symbolic
+// name, or TokenInvalidType if there is none.
+func (b *BaseRecognizer) GetTokenType(tokenName string) int {
+	if ttype, ok := b.GetTokenTypeMap()[tokenName]; ok {
+		return ttype
+	}
+
+	return TokenInvalidType
+}
 
 // What is the error header, normally line/character position information?//
 func (b *BaseRecognizer) GetErrorHeader(e RecognitionException) string {
@@ -0,0 +1,85 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestRecognizerNameMaps(t *testing.T) {
+	assert := assertNew(t)
+	lexer := NewLexerB(nil)
+
+	ruleIndexes := lexer.GetRuleIndexMap()
+	assert.Equal(len(lexer.RuleNames), len(ruleIndexes))
+	assert.Equal(3, ruleIndexes["ASSIGN"])
+
+	tokenTypes := lexer.GetTokenTypeMap()
+	assert.Equal(LexerBASSIGN, tokenTypes["ASSIGN"])
+	assert.Equal(LexerBASSIGN, tokenTypes["'='"])
+	assert.Equal(TokenEOF, tokenTypes["EOF"])
+	assert.Equal(LexerBINT, lexer.GetTokenType("INT"))
+	assert.Equal(TokenInvalidType, lexer.GetTokenType("NOPE"))
+
+	// recognizers of the same grammar share the cached maps
+	other := NewLexerB(nil)
+	assert.Equal(true, other.GetVocabulary() == lexer.GetVocabulary())
+}
+
+func TestRecognizerNameMapsSubSlice(t *testing.T) {
+	assert := assertNew(t)
+	atn := NewATN(ATNTypeParser, 0)
+	names := []string{"a", "b", "c"}
+
+	newRecognizer := func(ruleNames []string) *BaseRecognizer {
+		recognizer := NewBaseRecognizer()
+		recognizer.grammarATN = func() *ATN { return atn }
+		recognizer.RuleNames = ruleNames
+		recognizer.LiteralNames = ruleNames
+		return recognizer
+	}
+
+	short := newRecognizer(names[:1])
+	assert.Equal(map[string]int{"a": 0}, short.GetRuleIndexMap())
+	assert.Equal(0, short.GetVocabulary().MaxTokenType())
+
+	full := newRecognizer(names)
+	assert.Equal(map[string]int{"a": 0, "b": 1, "c": 2}, full.GetRuleIndexMap())
+	assert.Equal(2, full.GetVocabulary().MaxTokenType())
+
+	// a recognizer whose names change gets a map for the new names
+	short.RuleNames = names
+	assert.Equal(3, len(short.GetRuleIndexMap()))
+
+	// without an ATN the maps are built for the recognizer alone
+	recognizer := NewBaseRecognizer()
+	recognizer.RuleNames = names
+	assert.Equal(2, recognizer.GetRuleIndexMap()["c"])
+}
+
+func TestRecognizerNameMapsConcurrent(t *testing.T) {
+	assert := assertNew(t)
+
+	var wg sync.WaitGroup
+	results := make([]int, 16)
+	ruleIndexMaps := make([]map[string]int, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lexer := NewLexerB(nil)
+			ruleIndexMaps[i] = lexer.GetRuleIndexMap()
+			results[i] = ruleIndexMaps[i]["SEMI"] + lexer.GetTokenType("'='")
+		}(i)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		assert.Equal(2+LexerBASSIGN, result)
+		assert.Equal(reflect.ValueOf(ruleIndexMaps[0]).Pointer(), reflect.ValueOf(ruleIndexMaps[i]).Pointer())
+	}
+}
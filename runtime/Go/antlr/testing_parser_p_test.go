@@ -0,0 +1,55 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+/*
+ParserP is a parser ATN for testing purpose, using the tokens of LexerB.
+
+This file is generated from this grammar.
+
+parser grammar ParserP;
+
+options { tokenVocab = LexerB; }
+
+s : stat+ EOF ;
+stat : ID ASSIGN expr SEMI | expr SEMI ;
+expr : expr MULT expr | expr PLUS expr | INT | ID ;
+*/
+
+var parserP_serializedAtn = []uint16{
+	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 3, 9, 45, 4,
+	2, 9, 2, 4, 3, 9, 3, 4, 4, 9, 4, 6, 2, 9, 10, 2, 13, 2, 14, 2, 10, 3, 2,
+	3, 2, 3, 2, 3, 2, 5, 3, 17, 10, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 5, 4, 27, 10, 4, 3, 4, 3, 4, 3, 4, 3, 4, 12, 4, 7, 4, 34,
+	10, 4, 11, 4, 14, 4, 35, 3, 4, 3, 4, 3, 4, 3, 4, 3, 4, 3, 4, 3, 4, 3, 4,
+	2, 3, 6, 5, 2, 4, 6, 2, 2, 2, 47, 2, 8, 3, 2, 2, 2, 8, 12, 3, 2, 2, 2,
+	12, 13, 5, 4, 3, 2, 13, 9, 3, 2, 2, 2, 9, 10, 3, 2, 2, 2, 10, 8, 3, 2,
+	2, 2, 10, 11, 3, 2, 2, 2, 11, 14, 3, 2, 2, 2, 14, 15, 7, 2, 2, 3, 15, 3,
+	3, 2, 2, 2, 4, 16, 3, 2, 2, 2, 16, 18, 3, 2, 2, 2, 16, 23, 3, 2, 2, 2,
+	18, 19, 7, 3, 2, 2, 19, 20, 7, 6, 2, 2, 20, 21, 5, 6, 4, 2, 21, 22, 7,
+	5, 2, 2, 22, 17, 3, 2, 2, 2, 23, 24, 5, 6, 4, 2, 24, 25, 7, 5, 2, 2, 25,
+	17, 3, 2, 2, 2, 17, 5, 3, 2, 2, 2, 6, 26, 3, 2, 2, 2, 26, 28, 3, 2, 2,
+	2, 26, 29, 3, 2, 2, 2, 28, 30, 7, 4, 2, 2, 30, 27, 3, 2, 2, 2, 29, 31,
+	7, 3, 2, 2, 31, 27, 3, 2, 2, 2, 27, 32, 3, 2, 2, 2, 32, 33, 3, 2, 2, 2,
+	32, 36, 3, 2, 2, 2, 33, 37, 3, 2, 2, 2, 33, 41, 3, 2, 2, 2, 37, 38, 12,
+	4, 2, 2, 38, 39, 7, 8, 2, 2, 39, 40, 5, 6, 4, 5, 40, 34, 3, 2, 2, 2, 41,
+	42, 12, 3, 2, 2, 42, 43, 7, 7, 2, 2, 43, 44, 5, 6, 4, 4, 44, 34, 3, 2,
+	2, 2, 34, 35, 3, 2, 2, 2, 35, 32, 3, 2, 2, 2, 36, 7, 3, 2, 2, 2, 8, 8,
+	10, 16, 26, 33, 32,
+}
+
+var parserP_deserializer = NewATNDeserializer(nil)
+var parserP_atn = parserP_deserializer.DeserializeFromUInt16(parserP_serializedAtn)
+
+var parserP_ruleNames = []string{
+	"s", "stat", "expr",
+}
+
+// ParserP rules.
+const (
+	ParserPRULE_s    = 0
+	ParserPRULE_stat = 1
+	ParserPRULE_expr = 2
+)
@@ -14,6 +14,13 @@ type TokenSourceCharStreamPair struct {
 	charStream  CharStream
 }
 
+// NewTokenSourceCharStreamPair returns the pair passed to a TokenFactory by
+// a token source, such as one implemented outside this package, to record
+// where its tokens come from.
+func NewTokenSourceCharStreamPair(source TokenSource, input CharStream) *TokenSourceCharStreamPair {
+	return &TokenSourceCharStreamPair{source, input}
+}
+
 // A token has properties: text, type, line, character position in the line
 // (so we can ignore tabs), token channel, index, and source from which
 // we obtained this token.
@@ -26,6 +33,8 @@ type Token interface {
 	GetStop() int
 	GetLine() int
 	GetColumn() int
+	GetEndLine() int
+	GetEndColumn() int
 
 	GetText() string
 	SetText(s string)
@@ -37,6 +46,23 @@ type Token interface {
 	GetInputStream() CharStream
 }
 
+// ByteRangeToken is a Token that can locate itself in the UTF-8 source of a
+// ByteOffsetCharStream, such as the CommonTokens a lexer creates. Callers
+// needing byte offsets test for it with a type assertion:
+//
+//	if t, ok := token.(ByteRangeToken); ok {
+//		start, end := t.GetByteRange()
+//		...
+//	}
+type ByteRangeToken interface {
+	Token
+
+	// GetByteRange returns the byte offset of the token's first character
+	// and the offset just past its last character, or -1, -1 if the
+	// token's input stream does not implement ByteOffsetCharStream.
+	GetByteRange() (start, end int)
+}
+
 type BaseToken struct {
 	source     *TokenSourceCharStreamPair
 	tokenType  int    // token type of the token
@@ -46,6 +72,8 @@ type BaseToken struct {
 	tokenIndex int    // from 0..n-1 of the token object in the input stream
 	line       int    // line=1..n of the 1st character
 	column     int    // beginning of the line at which it occurs, 0..n-1
+	endLine    int    // line=1..n just past the last character
+	endColumn  int    // column 0..n just past the last character
 	text       string // text of the token.
 	readOnly   bool
 }
@@ -61,6 +89,11 @@ const (
 
 	TokenEOF = -1
 
+	// A lexer reading from an AppendableInputStream that is still open
+	// returns a token of this type when it reaches the end of the available
+	// text before it can finish the current token.
+	TokenNeedMoreInput = -4
+
 	// All tokens go to the parser (unless Skip() is called in that rule)
 	// on a particular "channel". The parser tunes to a particular channel
 	// so that whitespace etc... can go to the parser on a "hidden" channel.
@@ -93,6 +126,20 @@ func (b *BaseToken) GetColumn() int {
 	return b.column
 }
 
+// GetEndLine returns the line of the position just past the token's last
+// character. It differs from GetLine for tokens spanning several lines, such
+// as block comments.
+func (b *BaseToken) GetEndLine() int {
+	return b.endLine
+}
+
+// GetEndColumn returns the column of the position just past the token's last
+// character, so a token occupies the columns GetColumn to GetEndColumn-1 when
+// it fits on one line.
+func (b *BaseToken) GetEndColumn() int {
+	return b.endColumn
+}
+
 func (b *BaseToken) GetTokenType() int {
 	return b.tokenType
 }
@@ -117,10 +164,25 @@ func (b *BaseToken) GetInputStream() CharStream {
 	return b.source.charStream
 }
 
+// GetByteRange implements ByteRangeToken.
+func (b *BaseToken) GetByteRange() (start, end int) {
+	if b.source == nil {
+		return -1, -1
+	}
+	input, ok := b.source.charStream.(ByteOffsetCharStream)
+	if !ok || b.start < 0 {
+		return -1, -1
+	}
+
+	return input.ByteOffset(b.start), input.ByteOffset(b.stop + 1)
+}
+
 type CommonToken struct {
 	*BaseToken
 }
 
+var _ ByteRangeToken = &CommonToken{}
+
 func NewCommonToken(source *TokenSourceCharStreamPair, tokenType, channel, start, stop int) *CommonToken {
 
 	t := new(CommonToken)
@@ -139,6 +201,8 @@ func NewCommonToken(source *TokenSourceCharStreamPair, tokenType, channel, start
 	} else {
 		t.column = -1
 	}
+	t.endLine = t.line
+	t.endColumn = t.column
 	return t
 }
 
@@ -164,6 +228,8 @@ func (c *CommonToken) clone() *CommonToken {
 	t.tokenIndex = c.GetTokenIndex()
 	t.line = c.GetLine()
 	t.column = c.GetColumn()
+	t.endLine = c.GetEndLine()
+	t.endColumn = c.GetEndColumn()
 	t.text = c.GetText()
 	return t
 }
@@ -0,0 +1,234 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidTokenData is returned when decoding data that MarshalTokens did
+// not produce or that has been truncated.
+var ErrInvalidTokenData = errors.New("invalid or truncated token data")
+
+// tokenDataHeader starts the binary encoding; its last byte is the format
+// version.
+const tokenDataHeader = "ATK\x01"
+
+// MarshalTokens encodes the type, channel, start and stop indexes, line and
+// column range, token index and text of tokens in a compact binary form, for
+// caching the output of a lexer across runs.
+//
+// Positions are stored as small deltas from the previous token. Text that a
+// CommonToken takes from its input stream is not stored, as UnmarshalTokens
+// reattaches the stream; only text set explicitly, for example by a lexer
+// action, is written out.
+func MarshalTokens(tokens []Token) []byte {
+	data := make([]byte, 0, len(tokenDataHeader)+len(tokens)*12)
+	data = append(data, tokenDataHeader...)
+	data = appendUvarint(data, uint64(len(tokens)))
+
+	prevIndex, prevStop, prevLine := -1, -1, 1
+	for _, t := range tokens {
+		data = appendVarint(data, t.GetTokenType())
+		data = appendVarint(data, t.GetChannel())
+		data = appendVarint(data, t.GetTokenIndex()-prevIndex-1)
+		data = appendVarint(data, t.GetStart()-prevStop-1)
+		data = appendVarint(data, t.GetStop()-t.GetStart())
+		data = appendVarint(data, t.GetLine()-prevLine)
+		data = appendVarint(data, t.GetColumn())
+		data = appendVarint(data, t.GetEndLine()-t.GetLine())
+		data = appendVarint(data, t.GetEndColumn())
+
+		if ct, ok := t.(*CommonToken); ok && ct.text == "" {
+			data = appendUvarint(data, 0)
+		} else {
+			text := t.GetText()
+			data = appendUvarint(data, uint64(len(text))+1)
+			data = append(data, text...)
+		}
+
+		prevIndex, prevStop, prevLine = t.GetTokenIndex(), t.GetStop(), t.GetLine()
+	}
+
+	return data
+}
+
+// UnmarshalTokens decodes tokens encoded by MarshalTokens and attaches them
+// to input, which must hold the same text that was lexed to produce them.
+//
+// The tokens' TokenSource is a ListTokenSource over the decoded tokens. To
+// parse them again, wrap them in a token stream:
+//
+//	tokens, err := antlr.UnmarshalTokens(data, input)
+//	...
+//	stream := antlr.NewCommonTokenStream(antlr.NewListTokenSource(tokens), antlr.TokenDefaultChannel)
+func UnmarshalTokens(data []byte, input CharStream) ([]Token, error) {
+	if len(data) < len(tokenDataHeader) || string(data[:len(tokenDataHeader)]) != tokenDataHeader {
+		return nil, ErrInvalidTokenData
+	}
+
+	d := tokenDecoder{data: data[len(tokenDataHeader):]}
+	n := d.uvarint()
+	if n > uint64(len(d.data)) { // every token takes at least one byte
+		return nil, ErrInvalidTokenData
+	}
+
+	source := NewListTokenSource(nil)
+	pair := &TokenSourceCharStreamPair{source, input}
+	tokens := make([]Token, n)
+
+	prevIndex, prevStop, prevLine := -1, -1, 1
+	for i := range tokens {
+		t := &BaseToken{source: pair}
+		t.tokenType = d.varint()
+		t.channel = d.varint()
+		t.tokenIndex = prevIndex + 1 + d.varint()
+		t.start = prevStop + 1 + d.varint()
+		t.stop = t.start + d.varint()
+		t.line = prevLine + d.varint()
+		t.column = d.varint()
+		t.endLine = t.line + d.varint()
+		t.endColumn = d.varint()
+
+		if textLen := d.uvarint(); textLen > 0 {
+			t.text = d.string(textLen - 1)
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+
+		tokens[i] = &CommonToken{BaseToken: t}
+		prevIndex, prevStop, prevLine = t.tokenIndex, t.stop, t.line
+	}
+	if len(d.data) != 0 {
+		return nil, ErrInvalidTokenData
+	}
+
+	source.tokens = tokens
+	return tokens, nil
+}
+
+// tokenJSON is the JSON form of a token.
+type tokenJSON struct {
+	Type       int    `json:"type"`
+	Channel    int    `json:"channel"`
+	Start      int    `json:"start"`
+	Stop       int    `json:"stop"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	EndLine    int    `json:"endLine"`
+	EndColumn  int    `json:"endColumn"`
+	TokenIndex int    `json:"tokenIndex"`
+	Text       string `json:"text"`
+}
+
+// MarshalTokensJSON encodes tokens as a JSON array of objects with the same
+// fields as MarshalTokens. Unlike the binary form it always includes the
+// token text, so the output can be read without the input stream.
+func MarshalTokensJSON(tokens []Token) ([]byte, error) {
+	list := make([]tokenJSON, len(tokens))
+	for i, t := range tokens {
+		list[i] = tokenJSON{
+			Type:       t.GetTokenType(),
+			Channel:    t.GetChannel(),
+			Start:      t.GetStart(),
+			Stop:       t.GetStop(),
+			Line:       t.GetLine(),
+			Column:     t.GetColumn(),
+			EndLine:    t.GetEndLine(),
+			EndColumn:  t.GetEndColumn(),
+			TokenIndex: t.GetTokenIndex(),
+			Text:       t.GetText(),
+		}
+	}
+
+	return json.Marshal(list)
+}
+
+// UnmarshalTokensJSON decodes tokens encoded by MarshalTokensJSON and
+// attaches them to input, as UnmarshalTokens does.
+func UnmarshalTokensJSON(data []byte, input CharStream) ([]Token, error) {
+	var list []tokenJSON
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	source := NewListTokenSource(nil)
+	pair := &TokenSourceCharStreamPair{source, input}
+	tokens := make([]Token, len(list))
+	for i, tj := range list {
+		tokens[i] = &CommonToken{BaseToken: &BaseToken{
+			source:     pair,
+			tokenType:  tj.Type,
+			channel:    tj.Channel,
+			start:      tj.Start,
+			stop:       tj.Stop,
+			tokenIndex: tj.TokenIndex,
+			line:       tj.Line,
+			column:     tj.Column,
+			endLine:    tj.EndLine,
+			endColumn:  tj.EndColumn,
+			text:       tj.Text,
+		}}
+	}
+
+	source.tokens = tokens
+	return tokens, nil
+}
+
+func appendVarint(data []byte, v int) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(data, buf[:binary.PutVarint(buf[:], int64(v))]...)
+}
+
+func appendUvarint(data []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(data, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+// tokenDecoder reads the values written by MarshalTokens. After the first
+// error every read returns zero and err is ErrInvalidTokenData.
+type tokenDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *tokenDecoder) varint() int {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = ErrInvalidTokenData
+		d.data = nil
+		return 0
+	}
+	d.data = d.data[n:]
+
+	return int(v)
+}
+
+func (d *tokenDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = ErrInvalidTokenData
+		d.data = nil
+		return 0
+	}
+	d.data = d.data[n:]
+
+	return v
+}
+
+func (d *tokenDecoder) string(n uint64) string {
+	if n > uint64(len(d.data)) {
+		d.err = ErrInvalidTokenData
+		d.data = nil
+		return ""
+	}
+	s := string(d.data[:n])
+	d.data = d.data[n:]
+
+	return s
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+func lexForEncoding(src string) []Token {
+	stream := NewCommonTokenStream(NewLexerB(NewInputStream(src)), TokenDefaultChannel)
+	stream.Fill()
+	tokens := stream.GetAllTokens()
+	tokens[2].SetText("renamed")
+	return tokens
+}
+
+func assertSameTokens(assert *assert, expected, actual []Token) {
+	assert.Equal(len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(expected[i].GetTokenType(), actual[i].GetTokenType())
+		assert.Equal(expected[i].GetChannel(), actual[i].GetChannel())
+		assert.Equal(expected[i].GetStart(), actual[i].GetStart())
+		assert.Equal(expected[i].GetStop(), actual[i].GetStop())
+		assert.Equal(expected[i].GetLine(), actual[i].GetLine())
+		assert.Equal(expected[i].GetColumn(), actual[i].GetColumn())
+		assert.Equal(expected[i].GetEndLine(), actual[i].GetEndLine())
+		assert.Equal(expected[i].GetEndColumn(), actual[i].GetEndColumn())
+		assert.Equal(expected[i].GetTokenIndex(), actual[i].GetTokenIndex())
+		assert.Equal(expected[i].GetText(), actual[i].GetText())
+	}
+}
+
+func TestMarshalTokens(t *testing.T) {
+	assert := assertNew(t)
+	src := "abc = 12; x = y + 3"
+	tokens := lexForEncoding(src)
+
+	data := MarshalTokens(tokens)
+	input := NewInputStream(src)
+	decoded, err := UnmarshalTokens(data, input)
+	assert.Nil(err)
+	assertSameTokens(assert, tokens, decoded)
+	assert.Equal(CharStream(input), decoded[0].GetInputStream())
+
+	// the tokens can be parsed again without running the lexer
+	stream := NewCommonTokenStream(NewListTokenSource(decoded), TokenDefaultChannel)
+	stream.Fill()
+	assertSameTokens(assert, tokens, stream.GetAllTokens())
+	assert.Equal("abc renamed 12; x = y + 3", stream.GetAllText())
+}
+
+func TestMarshalTokensInvalid(t *testing.T) {
+	assert := assertNew(t)
+	data := MarshalTokens(lexForEncoding("a = 1"))
+	input := NewInputStream("a = 1")
+
+	for _, bad := range [][]byte{nil, []byte("XYZ\x01"), data[:len(data)-1], append(data, 0)} {
+		tokens, err := UnmarshalTokens(bad, input)
+		assert.Nil(tokens)
+		assert.Equal(ErrInvalidTokenData, err)
+	}
+}
+
+func TestMarshalTokensJSON(t *testing.T) {
+	assert := assertNew(t)
+	src := "abc = 12; x = y + 3"
+	tokens := lexForEncoding(src)
+
+	data, err := MarshalTokensJSON(tokens[:1])
+	assert.Nil(err)
+	assert.Equal(`[{"type":1,"channel":0,"start":0,"stop":2,"line":1,"column":0,"endLine":1,"endColumn":3,"tokenIndex":0,"text":"abc"}]`, string(data))
+
+	data, err = MarshalTokensJSON(tokens)
+	assert.Nil(err)
+	decoded, err := UnmarshalTokensJSON(data, NewInputStream(src))
+	assert.Nil(err)
+	assertSameTokens(assert, tokens, decoded)
+
+	_, err = UnmarshalTokensJSON([]byte("{"), nil)
+	assert.NotNil(err)
+}
@@ -4,6 +4,10 @@
 
 package antlr
 
+// TokenSource is the source of tokens for a TokenStream such as
+// CommonTokenStream. Lexers are the usual token sources, but any type may
+// implement it, for example a hand-written scanner, a token filter or an
+// adapter such as ListTokenSource or FuncTokenSource.
 type TokenSource interface {
 	NextToken() Token
 	Skip()
@@ -12,6 +16,6 @@ type TokenSource interface {
 	GetCharPositionInLine() int
 	GetInputStream() CharStream
 	GetSourceName() string
-	setTokenFactory(factory TokenFactory)
+	SetTokenFactory(factory TokenFactory)
 	GetTokenFactory() TokenFactory
 }
@@ -0,0 +1,31 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+func TestTokenEndPosition(t *testing.T) {
+	assert := assertNew(t)
+	lexer := NewLexerB(NewInputStream("abc  x"))
+	// make every space a line break so the WS token spans two lines
+	lexer.SetPositionTracking(&PositionTracking{LineTerminators: []rune{' '}})
+
+	stream := NewCommonTokenStream(lexer, TokenDefaultChannel)
+	stream.Fill()
+	tokens := stream.GetAllTokens()
+
+	positions := make([][4]int, len(tokens))
+	for i, tok := range tokens {
+		positions[i] = [4]int{tok.GetLine(), tok.GetColumn(), tok.GetEndLine(), tok.GetEndColumn()}
+	}
+	assert.Equal([][4]int{
+		{1, 0, 1, 3}, // abc
+		{1, 3, 3, 0}, // two spaces
+		{3, 0, 3, 1}, // x
+		{3, 1, 3, 1}, // EOF
+	}, positions)
+}
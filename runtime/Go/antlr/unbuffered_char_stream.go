@@ -0,0 +1,285 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// UnbufferedCharStream is a CharStream that pulls runes from an io.Reader on
+// demand. Unlike InputStream it does not hold the whole input in memory: it
+// keeps only a sliding window of the runes pinned by outstanding calls to
+// Mark, which lets a lexer process arbitrarily large input in bounded memory.
+//
+// Because released runes are discarded, GetText only works for intervals that
+// are still inside the window and Size cannot be known. Lexers reading from
+// this stream should therefore be given a TokenFactory that copies the token
+// text, for example lexer.SetTokenFactory(NewCommonTokenFactory(true)).
+type UnbufferedCharStream struct {
+	// data is a moving window buffer of the runes read from input. data[p] is
+	// LA(1). A TokenEOF entry is stored once the end of input is reached.
+	data []int
+
+	// n is the number of runes in data, data[0..n-1].
+	n int
+
+	// p is the index into data of the current rune, LA(1). The stream
+	// discards runes before p once numMarkers drops to zero.
+	p int
+
+	// numMarkers counts the outstanding markers. A marker is -(numMarkers)
+	// at the time Mark was called.
+	numMarkers int
+
+	// lastChar is the rune just before LA(1), returned by LA(-1).
+	lastChar int
+
+	// lastCharBufferStart is lastChar at the moment data[0] became LA(1). It
+	// restores LA(-1) when seeking back to the start of the buffer.
+	lastCharBufferStart int
+
+	// currentCharIndex is the absolute index of LA(1) in the input.
+	currentCharIndex int
+
+	input io.RuneReader
+	err   error
+	name  string
+}
+
+// NewUnbufferedCharStream creates a stream reading runes from input. If input
+// does not implement io.RuneReader it is wrapped in a bufio.Reader.
+func NewUnbufferedCharStream(input io.Reader) *UnbufferedCharStream {
+	u := new(UnbufferedCharStream)
+
+	if rr, ok := input.(io.RuneReader); ok {
+		u.input = rr
+	} else {
+		u.input = bufio.NewReader(input)
+	}
+	u.data = make([]int, 256)
+	u.lastChar = -1
+	u.lastCharBufferStart = -1
+	u.name = "<unknown>"
+
+	u.fill(1) // prime
+	return u
+}
+
+// SetSourceName sets the name returned by GetSourceName.
+func (u *UnbufferedCharStream) SetSourceName(name string) {
+	u.name = name
+}
+
+// Err returns the first error other than io.EOF returned by the underlying
+// reader. The stream treats such an error as the end of input.
+func (u *UnbufferedCharStream) Err() error {
+	return u.err
+}
+
+func (u *UnbufferedCharStream) Consume() {
+	if u.LA(1) == TokenEOF {
+		panic("cannot consume EOF")
+	}
+
+	// buf always has at least data[p==0] in this method due to ctor
+	u.lastChar = u.data[u.p] // track last char for LA(-1)
+
+	if u.p == u.n-1 && u.numMarkers == 0 {
+		u.n = 0
+		u.p = -1 // p++ will leave this at 0
+		u.lastCharBufferStart = u.lastChar
+	}
+
+	u.p++
+	u.currentCharIndex++
+	u.sync(1)
+}
+
+// sync makes sure we have 'want' elements from current position p. Last valid
+// p index is len(data)-1. p+want-1 is the data index 'want' elements ahead.
+func (u *UnbufferedCharStream) sync(want int) {
+	need := (u.p + want - 1) - u.n + 1 // how many more elements we need?
+	if need > 0 {
+		u.fill(need)
+	}
+}
+
+// fill adds n runes to the buffer and returns how many were actually added.
+// The count is less than n only when the end of input was reached.
+func (u *UnbufferedCharStream) fill(n int) int {
+	for i := 0; i < n; i++ {
+		if u.n > 0 && u.data[u.n-1] == TokenEOF {
+			return i
+		}
+		u.add(u.nextChar())
+	}
+
+	return n
+}
+
+// nextChar reads the next rune from input, or returns TokenEOF at the end of
+// input.
+func (u *UnbufferedCharStream) nextChar() int {
+	r, _, err := u.input.ReadRune()
+	if err != nil {
+		if err != io.EOF && u.err == nil {
+			u.err = err
+		}
+		return TokenEOF
+	}
+
+	return int(r)
+}
+
+func (u *UnbufferedCharStream) add(c int) {
+	if u.n >= len(u.data) {
+		data := make([]int, len(u.data)*2)
+		copy(data, u.data)
+		u.data = data
+	}
+	u.data[u.n] = c
+	u.n++
+}
+
+func (u *UnbufferedCharStream) LA(i int) int {
+	if i == -1 {
+		return u.lastChar // special case
+	}
+	u.sync(i)
+	index := u.p + i - 1
+	if index < 0 {
+		panic("index out of range: " + strconv.Itoa(index))
+	}
+	if index >= u.n {
+		return TokenEOF
+	}
+
+	return u.data[index]
+}
+
+// Mark returns a marker that we can release later.
+//
+// The specific marker value used for this class allows for some level of
+// protection against misuse where Seek is called on a mark or Release is
+// called in the wrong order.
+func (u *UnbufferedCharStream) Mark() int {
+	if u.numMarkers == 0 {
+		u.lastCharBufferStart = u.lastChar
+	}
+
+	mark := -u.numMarkers - 1
+	u.numMarkers++
+	return mark
+}
+
+// Release decrements the number of markers. When no markers remain, the runes
+// before the current position are discarded from the buffer.
+func (u *UnbufferedCharStream) Release(marker int) {
+	expectedMark := -u.numMarkers
+	if marker != expectedMark {
+		panic("release() called with an invalid marker.")
+	}
+
+	u.numMarkers--
+	if u.numMarkers == 0 && u.p > 0 { // release buffer when we can, but don't do unnecessary work
+		// Copy data[p]..data[n-1] to data[0]..data[(n-1)-p], reset ptrs
+		// p is last valid char; move nothing if p==n as we have no valid char
+		copy(u.data, u.data[u.p:u.n])
+		u.n = u.n - u.p
+		u.p = 0
+		u.lastCharBufferStart = u.lastChar
+	}
+}
+
+func (u *UnbufferedCharStream) Index() int {
+	return u.currentCharIndex
+}
+
+// Seek moves the input pointer to index. Seeking backwards is only possible
+// within the region pinned by the outstanding markers.
+func (u *UnbufferedCharStream) Seek(index int) {
+	if index == u.currentCharIndex {
+		return
+	}
+
+	if index > u.currentCharIndex {
+		u.sync(index - u.currentCharIndex)
+		index = intMin(index, u.getBufferStartIndex()+u.n-1)
+	}
+
+	// index == to bufferStartIndex should set p to 0
+	i := index - u.getBufferStartIndex()
+	if i < 0 {
+		panic("cannot seek to negative index " + strconv.Itoa(index))
+	} else if i >= u.n {
+		panic("seek to index outside buffer: " + strconv.Itoa(index) + " not in " +
+			strconv.Itoa(u.getBufferStartIndex()) + ".." + strconv.Itoa(u.getBufferStartIndex()+u.n))
+	}
+
+	u.p = i
+	u.currentCharIndex = index
+	if u.p == 0 {
+		u.lastChar = u.lastCharBufferStart
+	} else {
+		u.lastChar = u.data[u.p-1]
+	}
+}
+
+// Size panics as an unbuffered stream cannot know how many runes its input
+// holds.
+func (u *UnbufferedCharStream) Size() int {
+	panic("Unbuffered stream cannot know its size")
+}
+
+func (u *UnbufferedCharStream) GetSourceName() string {
+	return u.name
+}
+
+// GetText returns the text between start and stop inclusive. Both indexes
+// must lie within the buffered window.
+func (u *UnbufferedCharStream) GetText(start int, stop int) string {
+	if start < 0 || stop < start-1 {
+		panic("invalid interval " + strconv.Itoa(start) + ".." + strconv.Itoa(stop))
+	}
+
+	bufferStartIndex := u.getBufferStartIndex()
+	if u.n > 0 && u.data[u.n-1] == TokenEOF {
+		// the EOF sentinel is not part of the text
+		stop = intMin(stop, bufferStartIndex+u.n-2)
+	}
+	if start < bufferStartIndex || stop >= bufferStartIndex+u.n {
+		panic("interval " + strconv.Itoa(start) + ".." + strconv.Itoa(stop) + " outside buffer: " +
+			strconv.Itoa(bufferStartIndex) + ".." + strconv.Itoa(bufferStartIndex+u.n-1))
+	}
+	if stop < start {
+		return ""
+	}
+
+	i := start - bufferStartIndex
+	runes := make([]rune, stop-start+1)
+	for j := range runes {
+		runes[j] = rune(u.data[i+j])
+	}
+
+	return string(runes)
+}
+
+func (u *UnbufferedCharStream) GetTextFromTokens(start, stop Token) string {
+	if start != nil && stop != nil {
+		return u.GetText(start.GetStart(), stop.GetStop())
+	}
+
+	return ""
+}
+
+func (u *UnbufferedCharStream) GetTextFromInterval(i *Interval) string {
+	return u.GetText(i.Start, i.Stop)
+}
+
+func (u *UnbufferedCharStream) getBufferStartIndex() int {
+	return u.currentCharIndex - u.p
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnbufferedCharStreamLookahead(t *testing.T) {
+	assert := assertNew(t)
+	input := NewUnbufferedCharStream(strings.NewReader("xyz"))
+
+	assert.Equal(int('x'), input.LA(1))
+	assert.Equal(int('y'), input.LA(2))
+	assert.Equal(int('z'), input.LA(3))
+	assert.Equal(TokenEOF, input.LA(4))
+
+	input.Consume()
+	assert.Equal(int('x'), input.LA(-1))
+	assert.Equal(int('y'), input.LA(1))
+	assert.Equal(1, input.Index())
+
+	input.Consume()
+	input.Consume()
+	assert.Equal(TokenEOF, input.LA(1))
+	assert.Panics(input.Consume)
+}
+
+func TestUnbufferedCharStreamMarkSeek(t *testing.T) {
+	assert := assertNew(t)
+	input := NewUnbufferedCharStream(strings.NewReader("héllo"))
+
+	input.Consume()
+	m := input.Mark()
+	input.Consume()
+	input.Consume()
+	assert.Equal("él", input.GetText(1, 2))
+
+	input.Seek(1)
+	assert.Equal(int('é'), input.LA(1))
+	assert.Equal(int('h'), input.LA(-1))
+
+	input.Release(m)
+	input.Seek(3)
+	input.Consume()
+	assert.Equal(int('o'), input.LA(1))
+
+	// everything before the current rune has been discarded
+	assert.Panics(func() { input.GetText(1, 2) })
+	assert.Panics(func() { input.Seek(0) })
+}
+
+func TestUnbufferedCharStreamLexer(t *testing.T) {
+	assert := assertNew(t)
+	src := strings.Repeat("abc = 123; ", 2000)
+
+	input := NewUnbufferedCharStream(strings.NewReader(src))
+	lexer := NewLexerB(input)
+	lexer.SetTokenFactory(NewCommonTokenFactory(true))
+
+	expected := NewLexerB(NewInputStream(src)).GetAllTokens()
+	actual := lexer.GetAllTokens()
+
+	assert.Equal(len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(expected[i].GetTokenType(), actual[i].GetTokenType())
+		assert.Equal(expected[i].GetText(), actual[i].GetText())
+		assert.Equal(expected[i].GetStart(), actual[i].GetStart())
+	}
+
+	// the window only ever held a single token's worth of input
+	assert.Equal(true, len(input.data) < 1024)
+}
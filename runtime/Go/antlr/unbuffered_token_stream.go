@@ -0,0 +1,301 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// UnbufferedTokenStream is a TokenStream that pulls tokens from a TokenSource
+// on demand and keeps only the tokens pinned by outstanding calls to Mark.
+// Memory use is therefore bounded by the lookahead the parser needs rather
+// than by the size of the input, which suits streaming, record-at-a-time
+// parsers that run with BuildParseTrees set to false.
+//
+// The stream does not filter tokens by channel; lexers feeding it should
+// Skip tokens the parser is not interested in. Get and the GetText methods
+// only work for tokens that are still inside the buffered window, and Size
+// cannot be known.
+type UnbufferedTokenStream struct {
+	tokenSource TokenSource
+
+	// tokens is a moving window buffer of the data being scanned. While
+	// there's a marker, we keep adding to the buffer. Otherwise, Consume
+	// resets so we start filling at index 0 again.
+	tokens []Token
+
+	// n is the number of tokens in tokens, tokens[0..n-1].
+	n int
+
+	// p is the index into tokens of the current token, LT(1). The stream
+	// discards tokens before p once numMarkers drops to zero.
+	p int
+
+	// numMarkers counts the outstanding markers. A marker is -(numMarkers)
+	// at the time Mark was called.
+	numMarkers int
+
+	// lastToken is the token just before LT(1), returned by LT(-1).
+	lastToken Token
+
+	// lastTokenBufferStart is lastToken at the moment tokens[0] became LT(1).
+	// It restores LT(-1) when seeking back to the start of the buffer.
+	lastTokenBufferStart Token
+
+	// currentTokenIndex is the absolute token index of LT(1). It counts
+	// every token fetched from the source, including those already
+	// discarded from the buffer.
+	currentTokenIndex int
+}
+
+// NewUnbufferedTokenStream creates a stream reading tokens from tokenSource.
+func NewUnbufferedTokenStream(tokenSource TokenSource) *UnbufferedTokenStream {
+	u := new(UnbufferedTokenStream)
+
+	u.tokenSource = tokenSource
+	u.tokens = make([]Token, 16)
+	u.fill(1) // prime the pump
+
+	return u
+}
+
+func (u *UnbufferedTokenStream) Get(i int) Token {
+	bufferStartIndex := u.getBufferStartIndex()
+	if i < bufferStartIndex || i >= bufferStartIndex+u.n {
+		panic("get(" + strconv.Itoa(i) + ") outside buffer: " +
+			strconv.Itoa(bufferStartIndex) + ".." + strconv.Itoa(bufferStartIndex+u.n))
+	}
+
+	return u.tokens[i-bufferStartIndex]
+}
+
+func (u *UnbufferedTokenStream) LT(i int) Token {
+	if i == -1 {
+		return u.lastToken
+	}
+
+	u.sync(i)
+	index := u.p + i - 1
+	if index < 0 {
+		panic("LT(" + strconv.Itoa(i) + ") gives negative index")
+	}
+
+	if index >= u.n {
+		// the last buffered token is EOF, which is returned for any
+		// lookahead past the end of input
+		return u.tokens[u.n-1]
+	}
+
+	return u.tokens[index]
+}
+
+func (u *UnbufferedTokenStream) LA(i int) int {
+	return u.LT(i).GetTokenType()
+}
+
+func (u *UnbufferedTokenStream) GetTokenSource() TokenSource {
+	return u.tokenSource
+}
+
+// SetTokenSource resets the stream to read from tokenSource.
+func (u *UnbufferedTokenStream) SetTokenSource(tokenSource TokenSource) {
+	u.tokenSource = tokenSource
+	u.n = 0
+	u.p = 0
+	u.numMarkers = 0
+	u.lastToken = nil
+	u.lastTokenBufferStart = nil
+	u.currentTokenIndex = 0
+	u.fill(1)
+}
+
+func (u *UnbufferedTokenStream) Consume() {
+	if u.LA(1) == TokenEOF {
+		panic("cannot consume EOF")
+	}
+
+	// buf always has at least tokens[p==0] in this method due to ctor
+	u.lastToken = u.tokens[u.p] // track last token for LT(-1)
+
+	// if we're at last token and no markers, opportunity to flush buffer
+	if u.p == u.n-1 && u.numMarkers == 0 {
+		u.n = 0
+		u.p = -1 // p++ will leave this at 0
+		u.lastTokenBufferStart = u.lastToken
+	}
+
+	u.p++
+	u.currentTokenIndex++
+	u.sync(1)
+}
+
+// sync makes sure we have 'need' elements from current position p. Last valid
+// p index is len(tokens)-1. p+need-1 is the tokens index 'need' elements
+// ahead. If we need 1 element, p+1-1==p must be less than len(tokens).
+func (u *UnbufferedTokenStream) sync(want int) {
+	need := (u.p + want - 1) - u.n + 1 // how many more elements we need?
+	if need > 0 {
+		u.fill(need)
+	}
+}
+
+// fill adds n elements to the buffer and returns the number of tokens
+// actually added. The count is less than n only when EOF was reached.
+func (u *UnbufferedTokenStream) fill(n int) int {
+	for i := 0; i < n; i++ {
+		if u.n > 0 && u.tokens[u.n-1].GetTokenType() == TokenEOF {
+			return i
+		}
+
+		u.add(u.tokenSource.NextToken())
+	}
+
+	return n
+}
+
+func (u *UnbufferedTokenStream) add(t Token) {
+	if u.n >= len(u.tokens) {
+		tokens := make([]Token, len(u.tokens)*2)
+		copy(tokens, u.tokens)
+		u.tokens = tokens
+	}
+
+	t.SetTokenIndex(u.getBufferStartIndex() + u.n)
+	u.tokens[u.n] = t
+	u.n++
+}
+
+// Mark returns a marker that we can release later.
+//
+// The specific marker value used for this class allows for some level of
+// protection against misuse where Seek is called on a mark or Release is
+// called in the wrong order.
+func (u *UnbufferedTokenStream) Mark() int {
+	if u.numMarkers == 0 {
+		u.lastTokenBufferStart = u.lastToken
+	}
+
+	mark := -u.numMarkers - 1
+	u.numMarkers++
+	return mark
+}
+
+// Release decrements the number of markers. When no markers remain, the tokens
+// before the current position are discarded from the buffer.
+func (u *UnbufferedTokenStream) Release(marker int) {
+	expectedMark := -u.numMarkers
+	if marker != expectedMark {
+		panic("release() called with an invalid marker.")
+	}
+
+	u.numMarkers--
+	if u.numMarkers == 0 { // can we release buffer?
+		if u.p > 0 {
+			// Copy tokens[p]..tokens[n-1] to tokens[0]..tokens[(n-1)-p], reset ptrs
+			// p is last valid token; move nothing if p==n as we have no valid token
+			copy(u.tokens, u.tokens[u.p:u.n])
+			for i := u.n - u.p; i < u.n; i++ {
+				u.tokens[i] = nil // let released tokens be collected
+			}
+			u.n = u.n - u.p
+			u.p = 0
+		}
+
+		u.lastTokenBufferStart = u.lastToken
+	}
+}
+
+func (u *UnbufferedTokenStream) Index() int {
+	return u.currentTokenIndex
+}
+
+// Seek moves the stream to the token at index. Seeking backwards is only
+// possible within the region pinned by the outstanding markers.
+func (u *UnbufferedTokenStream) Seek(index int) {
+	if index == u.currentTokenIndex {
+		return
+	}
+
+	if index > u.currentTokenIndex {
+		u.sync(index - u.currentTokenIndex)
+		index = intMin(index, u.getBufferStartIndex()+u.n-1)
+	}
+
+	bufferStartIndex := u.getBufferStartIndex()
+	i := index - bufferStartIndex
+	if i < 0 {
+		panic("cannot seek to negative index " + strconv.Itoa(index))
+	} else if i >= u.n {
+		panic("seek to index outside buffer: " + strconv.Itoa(index) + " not in " +
+			strconv.Itoa(bufferStartIndex) + ".." + strconv.Itoa(bufferStartIndex+u.n))
+	}
+
+	u.p = i
+	u.currentTokenIndex = index
+	if u.p == 0 {
+		u.lastToken = u.lastTokenBufferStart
+	} else {
+		u.lastToken = u.tokens[u.p-1]
+	}
+}
+
+// Size panics as an unbuffered stream cannot know how many tokens its source
+// produces.
+func (u *UnbufferedTokenStream) Size() int {
+	panic("Unbuffered stream cannot know its size")
+}
+
+func (u *UnbufferedTokenStream) GetSourceName() string {
+	return u.tokenSource.GetSourceName()
+}
+
+// GetAllText returns the text of the tokens currently held in the buffer.
+func (u *UnbufferedTokenStream) GetAllText() string {
+	bufferStartIndex := u.getBufferStartIndex()
+	return u.GetTextFromInterval(NewInterval(bufferStartIndex, bufferStartIndex+u.n-1))
+}
+
+func (u *UnbufferedTokenStream) GetTextFromTokens(start, end Token) string {
+	if start == nil || end == nil {
+		return ""
+	}
+
+	return u.GetTextFromInterval(NewInterval(start.GetTokenIndex(), end.GetTokenIndex()))
+}
+
+func (u *UnbufferedTokenStream) GetTextFromRuleContext(interval RuleContext) string {
+	return u.GetTextFromInterval(interval.GetSourceInterval())
+}
+
+func (u *UnbufferedTokenStream) GetTextFromInterval(interval *Interval) string {
+	bufferStartIndex := u.getBufferStartIndex()
+	bufferStopIndex := bufferStartIndex + u.n - 1
+
+	start := interval.Start
+	stop := interval.Stop
+	if start < bufferStartIndex || stop > bufferStopIndex {
+		panic("interval " + strconv.Itoa(start) + ".." + strconv.Itoa(stop) + " not in token buffer window: " +
+			strconv.Itoa(bufferStartIndex) + ".." + strconv.Itoa(bufferStopIndex))
+	}
+
+	a := start - bufferStartIndex
+	b := stop - bufferStartIndex
+
+	var buf bytes.Buffer
+	for i := a; i <= b; i++ {
+		t := u.tokens[i]
+		if t.GetTokenType() == TokenEOF {
+			break
+		}
+		buf.WriteString(t.GetText())
+	}
+
+	return buf.String()
+}
+
+func (u *UnbufferedTokenStream) getBufferStartIndex() int {
+	return u.currentTokenIndex - u.p
+}
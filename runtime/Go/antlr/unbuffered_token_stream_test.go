@@ -0,0 +1,82 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+func TestUnbufferedTokenStreamLookahead(t *testing.T) {
+	assert := assertNew(t)
+	tokens := NewUnbufferedTokenStream(NewLexerB(NewInputStream("x = 3;")))
+
+	assert.Equal(LexerBID, tokens.LA(1))
+	assert.Equal(LexerBWS, tokens.LA(2))
+	assert.Equal(LexerBASSIGN, tokens.LA(3))
+
+	tokens.Consume()
+	assert.Equal("x", tokens.LT(-1).GetText())
+	assert.Equal(1, tokens.Index())
+
+	for tokens.LA(1) != TokenEOF {
+		tokens.Consume()
+	}
+	assert.Equal(TokenEOF, tokens.LA(2))
+	assert.Equal(";", tokens.LT(-1).GetText())
+	assert.Panics(tokens.Consume)
+}
+
+func TestUnbufferedTokenStreamMarkSeek(t *testing.T) {
+	assert := assertNew(t)
+	tokens := NewUnbufferedTokenStream(NewLexerB(NewInputStream("a+b*c")))
+
+	tokens.Consume()
+	m := tokens.Mark()
+	tokens.Consume()
+	tokens.Consume()
+	assert.Equal("+b", tokens.GetTextFromInterval(NewInterval(1, 2)))
+	assert.Equal("+", tokens.Get(1).GetText())
+
+	tokens.Seek(1)
+	assert.Equal("+", tokens.LT(1).GetText())
+	assert.Equal("a", tokens.LT(-1).GetText())
+
+	tokens.Release(m)
+	tokens.Seek(3)
+	tokens.Consume()
+
+	// everything before the current token has been discarded
+	assert.Equal(1, tokens.n)
+	assert.Equal("c", tokens.Get(4).GetText())
+	assert.Panics(func() { tokens.Get(1) })
+	assert.Panics(func() { tokens.Seek(2) })
+}
+
+func TestUnbufferedTokenStreamParse(t *testing.T) {
+	assert := assertNew(t)
+
+	// LexerB puts whitespace on the default channel, which an unbuffered
+	// stream does not filter, so the input has none
+	tokens := NewUnbufferedTokenStream(NewLexerB(NewInputStream("a=1;b=*2;c=3*4+5;d;")))
+	vocabulary := NewVocabulary(lexerB_lexerLiteralNames, lexerB_lexerSymbolicNames)
+	p := NewParserInterpreter("ParserP.g4", vocabulary, parserP_ruleNames, parserP_atn, tokens)
+	p.BuildParseTrees = false
+	errors := &syntaxErrorCounter{DefaultErrorListener: NewDefaultErrorListener()}
+	p.RemoveErrorListeners()
+	p.AddErrorListener(errors)
+
+	tree := p.Parse(ParserPRULE_s)
+
+	assert.Equal([]string{"extraneous input '*' expecting {ID, INT}"}, errors.errors)
+	assert.Equal(0, tree.GetChildCount())
+	assert.Equal(TokenEOF, tokens.LA(1))
+	assert.Equal(19, tokens.Index())
+
+	// recovery and prediction released their marks, so the stream only
+	// holds the last token
+	assert.Equal(0, tokens.numMarkers)
+	assert.Equal(1, tokens.n)
+	assert.Panics(func() { tokens.Get(0) })
+}
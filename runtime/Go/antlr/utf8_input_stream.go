@@ -0,0 +1,292 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// utf8CheckpointInterval is the number of runes between two entries of the
+// rune index to byte offset table kept by UTF8InputStream.
+const utf8CheckpointInterval = 64
+
+// ByteOffsetCharStream is a CharStream over UTF-8 encoded source that can
+// translate between rune indexes, as used by the lexer and by Token.GetStart
+// and Token.GetStop, and byte offsets into the source.
+type ByteOffsetCharStream interface {
+	CharStream
+
+	// ByteOffset returns the byte offset of the rune at index. An index at or
+	// past the end of input yields the length of the source.
+	ByteOffset(index int) int
+
+	// RuneIndex returns the index of the rune containing the byte at offset.
+	RuneIndex(offset int) int
+}
+
+// UTF8InputStream is a CharStream that decodes UTF-8 source lazily instead of
+// converting it to a []rune up front, so it uses one byte per ASCII character
+// rather than four. Invalid UTF-8 bytes are each seen as one
+// utf8.RuneError rune by the lexer, while GetText returns the original bytes.
+//
+// Besides the CharStream methods, UTF8InputStream implements
+// ByteOffsetCharStream. It records the byte offset of every
+// utf8CheckpointInterval-th rune as decoding proceeds, so mapping a rune index
+// to a byte offset takes constant time and the reverse takes O(log n).
+// Tokens lexed from it report their byte offsets through ByteRangeToken.
+type UTF8InputStream struct {
+	data []byte
+
+	// index is the rune index of LA(1) and pos its byte offset.
+	index int
+	pos   int
+
+	// offsets[k] is the byte offset of rune k*utf8CheckpointInterval. The
+	// table grows as the stream is decoded.
+	offsets []int
+
+	// size is the number of runes in data, or -1 until it is known.
+	size int
+
+	// lineIndex is built on first use by the position lookup methods.
+	lineIndex *LineIndex
+}
+
+// NewUTF8InputStream creates a stream over the UTF-8 encoded data. The slice
+// is not copied and must not be modified while the stream is in use.
+func NewUTF8InputStream(data []byte) *UTF8InputStream {
+	is := new(UTF8InputStream)
+
+	is.data = data
+	is.offsets = []int{0}
+	is.size = -1
+
+	return is
+}
+
+func (is *UTF8InputStream) reset() {
+	is.index = 0
+	is.pos = 0
+}
+
+func (is *UTF8InputStream) Consume() {
+	if is.pos >= len(is.data) {
+		// assert is.LA(1) == TokenEOF
+		panic("cannot consume EOF")
+	}
+	_, n := utf8.DecodeRune(is.data[is.pos:])
+	is.pos += n
+	is.index++
+	if is.index%utf8CheckpointInterval == 0 && is.index/utf8CheckpointInterval == len(is.offsets) {
+		is.offsets = append(is.offsets, is.pos)
+	}
+}
+
+func (is *UTF8InputStream) LA(offset int) int {
+
+	if offset == 0 {
+		return 0 // nil
+	}
+
+	var pos int
+	switch {
+	case offset == 1:
+		pos = is.pos
+	case offset == -1:
+		if is.pos == 0 {
+			return TokenEOF
+		}
+		r, _ := utf8.DecodeLastRune(is.data[:is.pos])
+		return int(r)
+	case offset < 0:
+		index := is.index + offset
+		if index < 0 {
+			return TokenEOF
+		}
+		pos = is.ByteOffset(index)
+	default:
+		pos = is.ByteOffset(is.index + offset - 1)
+	}
+
+	if pos >= len(is.data) {
+		return TokenEOF
+	}
+	r, _ := utf8.DecodeRune(is.data[pos:])
+	return int(r)
+}
+
+func (is *UTF8InputStream) LT(offset int) int {
+	return is.LA(offset)
+}
+
+func (is *UTF8InputStream) Index() int {
+	return is.index
+}
+
+func (is *UTF8InputStream) Size() int {
+	if is.size < 0 {
+		is.checkpoint(int(^uint(0) >> 1))
+		last := len(is.offsets) - 1
+		is.size = last*utf8CheckpointInterval + utf8.RuneCount(is.data[is.offsets[last]:])
+	}
+
+	return is.size
+}
+
+// mark/release do nothing we have entire buffer
+func (is *UTF8InputStream) Mark() int {
+	return -1
+}
+
+func (is *UTF8InputStream) Release(marker int) {
+}
+
+func (is *UTF8InputStream) Seek(index int) {
+	if index < 0 {
+		index = 0
+	}
+	if index == is.index {
+		return
+	}
+	is.pos = is.ByteOffset(index)
+	if is.pos >= len(is.data) {
+		index = intMin(index, is.Size())
+	}
+	is.index = index
+}
+
+// ByteOffset returns the byte offset in the source of the rune at index.
+func (is *UTF8InputStream) ByteOffset(index int) int {
+	if index == is.index {
+		return is.pos
+	}
+	if index <= 0 {
+		return 0
+	}
+
+	k := index / utf8CheckpointInterval
+	is.checkpoint(k)
+	if k >= len(is.offsets) {
+		return len(is.data)
+	}
+
+	pos := is.offsets[k]
+	for i := k * utf8CheckpointInterval; i < index && pos < len(is.data); i++ {
+		_, n := utf8.DecodeRune(is.data[pos:])
+		pos += n
+	}
+
+	return pos
+}
+
+// RuneIndex returns the index of the rune that contains the byte at offset.
+func (is *UTF8InputStream) RuneIndex(offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+	if offset >= len(is.data) {
+		return is.Size()
+	}
+
+	for is.offsets[len(is.offsets)-1] <= offset {
+		if !is.checkpoint(len(is.offsets)) {
+			break
+		}
+	}
+	k := sort.SearchInts(is.offsets, offset+1) - 1
+
+	index := k * utf8CheckpointInterval
+	pos := is.offsets[k]
+	for {
+		_, n := utf8.DecodeRune(is.data[pos:])
+		if pos+n > offset {
+			return index
+		}
+		pos += n
+		index++
+	}
+}
+
+// checkpoint decodes the source until offsets holds the entry for checkpoint k
+// or the end of the source is reached, and reports whether the entry exists.
+func (is *UTF8InputStream) checkpoint(k int) bool {
+	for len(is.offsets) <= k {
+		pos := is.offsets[len(is.offsets)-1]
+		for i := 0; i < utf8CheckpointInterval; i++ {
+			if pos >= len(is.data) {
+				return false
+			}
+			_, n := utf8.DecodeRune(is.data[pos:])
+			pos += n
+		}
+		is.offsets = append(is.offsets, pos)
+	}
+
+	return true
+}
+
+func (is *UTF8InputStream) GetText(start int, stop int) string {
+	if stop < start {
+		return ""
+	}
+	startPos := is.ByteOffset(start)
+	if startPos >= len(is.data) {
+		return ""
+	}
+
+	return string(is.data[startPos:is.ByteOffset(stop+1)])
+}
+
+func (is *UTF8InputStream) GetTextFromTokens(start, stop Token) string {
+	if start != nil && stop != nil {
+		return is.GetText(start.GetStart(), stop.GetStop())
+	}
+
+	return ""
+}
+
+func (is *UTF8InputStream) GetTextFromInterval(i *Interval) string {
+	return is.GetText(i.Start, i.Stop)
+}
+
+func (*UTF8InputStream) GetSourceName() string {
+	return "Obtained from bytes"
+}
+
+func (is *UTF8InputStream) String() string {
+	return string(is.data)
+}
+
+// GetLineIndex returns the line table of the stream, building it on first
+// use. The table holds the decoded input at four bytes per character, which
+// costs the memory UTF8InputStream otherwise saves.
+func (is *UTF8InputStream) GetLineIndex() *LineIndex {
+	if is.lineIndex == nil {
+		is.lineIndex = newLineIndex([]rune(string(is.data)), nil)
+	}
+
+	return is.lineIndex
+}
+
+// PositionOf returns the line (1..n) and column (0..n-1) of the character at
+// index, for example the start of a token or of a RecognitionException's
+// offending token.
+func (is *UTF8InputStream) PositionOf(index int) (line, column int) {
+	return is.GetLineIndex().PositionOf(index)
+}
+
+// OffsetOf returns the index of the character at line and column, or -1 if
+// the line does not exist.
+func (is *UTF8InputStream) OffsetOf(line, column int) int {
+	return is.GetLineIndex().OffsetOf(line, column)
+}
+
+// LineText returns the text of line n without its line terminator, for
+// showing source snippets in diagnostics. Invalid UTF-8 is returned as is.
+func (is *UTF8InputStream) LineText(n int) string {
+	start, stop := is.GetLineIndex().lineSpan(n)
+	return string(is.data[is.ByteOffset(start):is.ByteOffset(stop)])
+}
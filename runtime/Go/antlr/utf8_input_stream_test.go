@@ -0,0 +1,67 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUTF8InputStreamMatchesInputStream(t *testing.T) {
+	assert := assertNew(t)
+	src := strings.Repeat("aé€😀\n", 50)
+
+	expected := NewInputStream(src)
+	actual := NewUTF8InputStream([]byte(src))
+
+	assert.Equal(expected.Size(), actual.Size())
+	for expected.LA(1) != TokenEOF {
+		assert.Equal(expected.LA(1), actual.LA(1))
+		assert.Equal(expected.LA(2), actual.LA(2))
+		assert.Equal(expected.LA(-1), actual.LA(-1))
+		expected.Consume()
+		actual.Consume()
+	}
+	assert.Equal(TokenEOF, actual.LA(1))
+	assert.Panics(actual.Consume)
+
+	actual.Seek(101)
+	assert.Equal(expected.GetText(101, 130), actual.GetText(101, 130))
+	assert.Equal(int([]rune(src)[101]), actual.LA(1))
+
+	actual.Seek(-5)
+	assert.Equal(0, actual.Index())
+	assert.Equal(0, actual.ByteOffset(actual.Index()))
+	assert.Equal(int([]rune(src)[0]), actual.LA(1))
+}
+
+func TestUTF8InputStreamByteOffsets(t *testing.T) {
+	assert := assertNew(t)
+	src := strings.Repeat("aé€😀", 40)
+	input := NewUTF8InputStream([]byte(src))
+
+	offset := 0
+	for i, r := range []rune(src) {
+		assert.Equal(offset, input.ByteOffset(i))
+		assert.Equal(i, input.RuneIndex(offset))
+		assert.Equal(i, input.RuneIndex(offset+len(string(r))-1))
+		offset += len(string(r))
+	}
+	assert.Equal(len(src), input.ByteOffset(input.Size()))
+	assert.Equal(input.Size(), input.RuneIndex(len(src)))
+}
+
+func TestUTF8InputStreamTokenByteRange(t *testing.T) {
+	assert := assertNew(t)
+	input := NewUTF8InputStream([]byte("abc = 42;"))
+	tokens := NewLexerB(input).GetAllTokens()
+
+	token, ok := tokens[4].(ByteRangeToken)
+	assert.Equal(true, ok)
+	start, end := token.GetByteRange()
+	assert.Equal("42", tokens[4].GetText())
+	assert.Equal(6, start)
+	assert.Equal(8, end)
+}
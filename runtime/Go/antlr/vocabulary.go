@@ -0,0 +1,116 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"strconv"
+)
+
+// Vocabulary maps the token types of a grammar to the names used to display
+// them.
+//
+// A token type may have a literal name, the quoted text of a token defined by
+// a literal such as '=' in the grammar, and a symbolic name, the name of the
+// rule or tokens entry defining it such as ASSIGN. Methods return "" for a
+// name that does not exist.
+type Vocabulary interface {
+	// MaxTokenType returns the highest token type with a name.
+	MaxTokenType() int
+
+	// GetLiteralName returns the literal name of tokenType, including its
+	// quotes, such as "'='".
+	GetLiteralName(tokenType int) string
+
+	// GetSymbolicName returns the symbolic name of tokenType, such as
+	// "ASSIGN". The symbolic name of TokenEOF is "EOF".
+	GetSymbolicName(tokenType int) string
+
+	// GetDisplayName returns the name to show for tokenType in messages: its
+	// literal name, or its symbolic name if it has none, or failing both the
+	// token type as a number.
+	GetDisplayName(tokenType int) string
+
+	// GetTokenType returns the token type with the given literal or symbolic
+	// name, or TokenInvalidType if there is none.
+	GetTokenType(name string) int
+}
+
+// BaseVocabulary is a Vocabulary over the literal and symbolic name slices
+// generated for each recognizer, both indexed by token type.
+type BaseVocabulary struct {
+	literalNames  []string
+	symbolicNames []string
+	maxTokenType  int
+	tokenTypes    map[string]int
+}
+
+// VocabularyEMPTY is a vocabulary with no token names.
+var VocabularyEMPTY = NewVocabulary(nil, nil)
+
+// NewVocabulary creates a vocabulary from the literal and symbolic names of a
+// grammar's tokens. Either slice may be nil.
+func NewVocabulary(literalNames, symbolicNames []string) *BaseVocabulary {
+
+	v := new(BaseVocabulary)
+
+	v.literalNames = literalNames
+	v.symbolicNames = symbolicNames
+	v.maxTokenType = intMax(len(literalNames), len(symbolicNames)) - 1
+
+	v.tokenTypes = make(map[string]int)
+	for i := 0; i <= v.maxTokenType; i++ {
+		if name := v.GetLiteralName(i); name != "" {
+			v.tokenTypes[name] = i
+		}
+		if name := v.GetSymbolicName(i); name != "" {
+			v.tokenTypes[name] = i
+		}
+	}
+	v.tokenTypes["EOF"] = TokenEOF
+
+	return v
+}
+
+func (v *BaseVocabulary) MaxTokenType() int {
+	return v.maxTokenType
+}
+
+func (v *BaseVocabulary) GetLiteralName(tokenType int) string {
+	if tokenType >= 0 && tokenType < len(v.literalNames) {
+		return v.literalNames[tokenType]
+	}
+
+	return ""
+}
+
+func (v *BaseVocabulary) GetSymbolicName(tokenType int) string {
+	if tokenType >= 0 && tokenType < len(v.symbolicNames) {
+		return v.symbolicNames[tokenType]
+	}
+	if tokenType == TokenEOF {
+		return "EOF"
+	}
+
+	return ""
+}
+
+func (v *BaseVocabulary) GetDisplayName(tokenType int) string {
+	if name := v.GetLiteralName(tokenType); name != "" {
+		return name
+	}
+	if name := v.GetSymbolicName(tokenType); name != "" {
+		return name
+	}
+
+	return strconv.Itoa(tokenType)
+}
+
+func (v *BaseVocabulary) GetTokenType(name string) int {
+	if ttype, ok := v.tokenTypes[name]; ok {
+		return ttype
+	}
+
+	return TokenInvalidType
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2012-2017 The ANTLR Project. All rights reserved.
+// Use of this file is governed by the BSD 3-clause license that
+// can be found in the LICENSE.txt file in the project root.
+
+package antlr
+
+import (
+	"testing"
+)
+
+func TestVocabularyNames(t *testing.T) {
+	assert := assertNew(t)
+	vocabulary := NewLexerB(nil).GetVocabulary()
+
+	assert.Equal(LexerBWS, vocabulary.MaxTokenType())
+
+	assert.Equal("'='", vocabulary.GetLiteralName(LexerBASSIGN))
+	assert.Equal("", vocabulary.GetLiteralName(LexerBID))
+	assert.Equal("ASSIGN", vocabulary.GetSymbolicName(LexerBASSIGN))
+	assert.Equal("EOF", vocabulary.GetSymbolicName(TokenEOF))
+	assert.Equal("", vocabulary.GetSymbolicName(42))
+
+	assert.Equal("'='", vocabulary.GetDisplayName(LexerBASSIGN))
+	assert.Equal("ID", vocabulary.GetDisplayName(LexerBID))
+	assert.Equal("42", vocabulary.GetDisplayName(42))
+
+	assert.Equal(LexerBASSIGN, vocabulary.GetTokenType("'='"))
+	assert.Equal(LexerBASSIGN, vocabulary.GetTokenType("ASSIGN"))
+	assert.Equal(TokenEOF, vocabulary.GetTokenType("EOF"))
+	assert.Equal(TokenInvalidType, vocabulary.GetTokenType("NOPE"))
+}
+
+func TestIntervalSetStringVocabulary(t *testing.T) {
+	assert := assertNew(t)
+	vocabulary := NewLexerB(nil).GetVocabulary()
+
+	set := NewIntervalSet()
+	set.addOne(TokenEOF)
+	set.addRange(LexerBINT, LexerBASSIGN)
+
+	assert.Equal("{<EOF>, INT, ';', '='}", set.StringVerbose(vocabulary, false))
+	assert.Equal("{<EOF>, 2..4}", set.String())
+	assert.Equal("{<EOF>, 2, 3, 4}", set.StringVerbose(VocabularyEMPTY, false))
+}